@@ -0,0 +1,538 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/greatcloak/decimal"
+	"github.com/wentaojin/scan/common"
+	"github.com/wentaojin/scan/config"
+	"github.com/wentaojin/scan/database"
+	"strings"
+	"sync"
+)
+
+// getCheckColumns 获取 init 阶段记录的表待扫描字段以及字段校验类型
+func getCheckColumns(ctx context.Context, dbS *database.MySQL, cfg *config.Config, t database.Wait) ([]database.CheckColumn, []map[string]string, error) {
+	columns, err := dbS.GetMySQLTableColumn(ctx, t.SchemaNameT, t.TableNameT)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	checkColumns, err := newCheckColumns(cfg, t.TableNameT, columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	originColumns := make(map[string]struct{})
+	for _, c := range strings.Split(t.ColumnDetailT, ",") {
+		originColumns[strings.ToUpper(c)] = struct{}{}
+	}
+
+	var results []database.CheckColumn
+	for _, c := range checkColumns {
+		if _, ok := originColumns[strings.ToUpper(c.ColumnName)]; ok {
+			results = append(results, c)
+		}
+	}
+
+	// oracle 时间无时区按目标端 time_zone 本地时间写入，timestamp 取值范围换算为本地时间
+	var offset *int64
+	for i := range results {
+		if results[i].DataType != "TIMESTAMP" {
+			continue
+		}
+		if offset == nil {
+			o, err := dbS.GetMySQLTimeZoneOffset(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+			offset = &o
+		}
+		results[i].TemporalMin, results[i].TemporalMax = common.MySQLTimestampRange(*offset)
+	}
+	return results, columns, nil
+}
+
+// resolveOracleColumns 映射后的源端字段名匹配 oracle 数据字典字段名，匹配规则与表名一致，字段不存在或者忽略大小写匹配多个字段返回错误
+func resolveOracleColumns(ctx context.Context, dbT *database.Oracle, t database.Wait, columns []database.CheckColumn) ([]database.CheckColumn, error) {
+	if len(columns) == 0 {
+		return columns, nil
+	}
+	oraColumns, err := dbT.GetOracleTableColumn(ctx, t.SchemaNameS, t.TableNameS)
+	if err != nil {
+		return nil, err
+	}
+	for i, c := range columns {
+		matched := matchOracleName(oraColumns, c.ColumnNameS)
+		switch len(matched) {
+		case 0:
+			return nil, fmt.Errorf("oracle table [%s.%s] column [%s] mapped from mysql column [%s] not found", t.SchemaNameS, t.TableNameS, c.ColumnNameS, c.ColumnName)
+		case 1:
+			columns[i].ColumnNameS = matched[0]
+		default:
+			return nil, fmt.Errorf("oracle table [%s.%s] column [%s] mapped from mysql column [%s] is ambiguous, matched columns [%s], please config [mapping] columns", t.SchemaNameS, t.TableNameS, c.ColumnNameS, c.ColumnName, strings.Join(matched, ","))
+		}
+	}
+	return columns, nil
+}
+
+// newCheckColumns 获取满足字段过滤规则的待扫描字段以及字段校验类型，并按映射规则设置源端字段名
+func newCheckColumns(cfg *config.Config, tableName string, columns []map[string]string) ([]database.CheckColumn, error) {
+	var matchColumns []map[string]string
+	for _, c := range columns {
+		if cfg.FilterConfig.MatchColumn(tableName, c["COLUMN_NAME"]) {
+			matchColumns = append(matchColumns, c)
+		}
+	}
+	checkColumns, err := database.NewCheckColumns(matchColumns, cfg.AppConfig.CheckTypes)
+	if err != nil {
+		return nil, err
+	}
+	for i := range checkColumns {
+		checkColumns[i].ColumnNameS = cfg.MappingConfig.OracleColumnName(tableName, checkColumns[i].ColumnName)
+	}
+	return checkColumns, nil
+}
+
+type columnCheck struct {
+	columnName string
+	checkType  string
+}
+
+// columnStat 表字段单个校验类型各 chunk 汇总统计
+type columnStat struct {
+	minValue         decimal.Decimal
+	maxValue         decimal.Decimal
+	hasValue         bool
+	nullCount        int64
+	notNullCount     int64
+	violateCount     int64
+	maxIntegerDigits int
+	maxScale         int
+	maxLength        int
+	maxByteLength    int
+	minTemporal      string
+	maxTemporal      string
+	chunkCount       int64
+	firstChunk       database.Summary
+	lastChunk        database.Summary
+}
+
+// columnState 汇总表各字段校验类型 chunk 统计，用于判定字段是否可 modify 以及建议类型
+type columnState struct {
+	mu    sync.Mutex
+	keys  []columnCheck
+	stats map[columnCheck]*columnStat
+}
+
+func newColumnState() *columnState {
+	return &columnState{
+		stats: make(map[columnCheck]*columnStat),
+	}
+}
+
+func (s *columnState) merge(summaries []database.Summary) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range summaries {
+		key := columnCheck{columnName: strings.ToUpper(r.ColumnName), checkType: strings.ToUpper(r.CheckType)}
+		st, ok := s.stats[key]
+		if !ok {
+			st = &columnStat{}
+			s.stats[key] = st
+			s.keys = append(s.keys, key)
+		}
+		st.nullCount += r.NullCount
+		st.notNullCount += r.NotNullCount
+		st.chunkCount++
+		if r.MaxIntegerDigits > st.maxIntegerDigits {
+			st.maxIntegerDigits = r.MaxIntegerDigits
+		}
+		if r.MaxScale > st.maxScale {
+			st.maxScale = r.MaxScale
+		}
+		if r.MaxLength > st.maxLength {
+			st.maxLength = r.MaxLength
+		}
+		if r.MaxByteLength > st.maxByteLength {
+			st.maxByteLength = r.MaxByteLength
+		}
+
+		if r.ViolateCount > 0 {
+			if st.violateCount == 0 || r.ChunkID < st.firstChunk.ChunkID {
+				st.firstChunk = r
+			}
+			if st.violateCount == 0 || r.ChunkID > st.lastChunk.ChunkID {
+				st.lastChunk = r
+			}
+			st.violateCount += r.ViolateCount
+		}
+
+		// 字段数据全为 NULL 或者字符类型字段无取值范围
+		if r.NotNullCount == 0 || strings.EqualFold(r.MinValue, "") {
+			continue
+		}
+		if strings.EqualFold(key.checkType, database.CheckTypeTemporal) {
+			if !st.hasValue || common.CompareTemporal(r.MinValue, st.minTemporal) == -1 {
+				st.minTemporal = r.MinValue
+			}
+			if !st.hasValue || common.CompareTemporal(r.MaxValue, st.maxTemporal) == 1 {
+				st.maxTemporal = r.MaxValue
+			}
+			st.hasValue = true
+			continue
+		}
+		minValue, err := decimal.NewFromString(r.MinValue)
+		if err != nil {
+			return err
+		}
+		maxValue, err := decimal.NewFromString(r.MaxValue)
+		if err != nil {
+			return err
+		}
+		if !st.hasValue || minValue.Cmp(st.minValue) == -1 {
+			st.minValue = minValue
+		}
+		if !st.hasValue || maxValue.Cmp(st.maxValue) == 1 {
+			st.maxValue = maxValue
+		}
+		st.hasValue = true
+	}
+	return nil
+}
+
+func (s *columnState) stat(columnName, checkType string) columnStat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if st, ok := s.stats[columnCheck{columnName: strings.ToUpper(columnName), checkType: strings.ToUpper(checkType)}]; ok {
+		return *st
+	}
+	return columnStat{}
+}
+
+// decided 字段各校验类型是否均已判定，继续扫描不影响结果
+func (s *columnState) decided(c database.CheckColumn) bool {
+	for _, t := range c.Checks {
+		st := s.stat(c.ColumnName, t)
+		switch t {
+		case database.CheckTypeInteger:
+			if !st.hasValue || common.NarrowestIntegerType(st.minValue, st.maxValue) != "" {
+				return false
+			}
+		case database.CheckTypeDecimal:
+			// 需扫描全部数据获取最大整数位以及小数位，无法扩大 decimal 容纳源端数据视为已判定
+			if st.violateCount == 0 {
+				return false
+			}
+			if _, ok := widenDecimalType(c, st); ok {
+				return false
+			}
+		case database.CheckTypeScale:
+			// 字段实际小数位已达定义小数位，无法缩小小数位
+			if st.maxScale < c.Scale {
+				return false
+			}
+		case database.CheckTypeFloat:
+			// 需扫描全部数据获取最大整数位以及小数位，无法使用 decimal 容纳源端数据视为已判定
+			if st.violateCount == 0 {
+				return false
+			}
+			if _, ok := fitDecimalType(st.maxIntegerDigits, st.maxScale); ok {
+				return false
+			}
+		case database.CheckTypeTemporal:
+			// 越界时间已超出 datetime 取值范围，无可容纳的时间类型
+			if st.violateCount == 0 || temporalFitDatetime(st) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// filter 剔除已判定的字段，返回剩余待扫描字段
+func (s *columnState) filter(columns []database.CheckColumn) []database.CheckColumn {
+	var results []database.CheckColumn
+	for _, c := range columns {
+		if !s.decided(c) {
+			results = append(results, c)
+		}
+	}
+	return results
+}
+
+// evaluated 字段各校验类型是否均存在 chunk 扫描统计
+func (s *columnState) evaluated(c database.CheckColumn) bool {
+	for _, t := range c.Checks {
+		if s.stat(c.ColumnName, t).chunkCount == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// recommend 返回字段建议修改类型，字段类型无需调整返回空字符串，字段不可 modify 返回 false
+func (s *columnState) recommend(c database.CheckColumn) (string, bool) {
+	if c.HasCheck(database.CheckTypeDecimal) {
+		st := s.stat(c.ColumnName, database.CheckTypeDecimal)
+		if st.violateCount > 0 {
+			return widenDecimalType(c, st)
+		}
+	}
+	if c.HasCheck(database.CheckTypeInteger) {
+		st := s.stat(c.ColumnName, database.CheckTypeInteger)
+		// 字段数据全为 NULL 或者表无数据，沿用 BIGINT(20)
+		if !st.hasValue {
+			return "BIGINT(20)", true
+		}
+		columnType := common.NarrowestIntegerType(st.minValue, st.maxValue)
+		return columnType, columnType != ""
+	}
+	if c.HasCheck(database.CheckTypeFloat) {
+		st := s.stat(c.ColumnName, database.CheckTypeFloat)
+		// 源端数值经浮点转换丢失精度，建议使用可精确容纳源端数据的 decimal
+		if st.violateCount > 0 {
+			return fitDecimalType(st.maxIntegerDigits, st.maxScale)
+		}
+		return "", true
+	}
+	if c.HasCheck(database.CheckTypeString) {
+		st := s.stat(c.ColumnName, database.CheckTypeString)
+		// 源端数据超出字段长度写入截断，建议可容纳源端数据的最小长度
+		if st.violateCount > 0 {
+			return fitStringType(c, st.maxLength, st.maxByteLength), true
+		}
+		return "", true
+	}
+	if c.HasCheck(database.CheckTypeTemporal) {
+		st := s.stat(c.ColumnName, database.CheckTypeTemporal)
+		if st.violateCount == 0 {
+			return "", true
+		}
+		// timestamp 越界时间处于 datetime 取值范围，建议 datetime，其余时间类型无可容纳的时间类型
+		if strings.EqualFold(c.DataType, "TIMESTAMP") && temporalFitDatetime(st) {
+			return common.MySQLDatetimeType(c.DatetimePrecision), true
+		}
+		return "", false
+	}
+	if c.HasCheck(database.CheckTypeScale) {
+		st := s.stat(c.ColumnName, database.CheckTypeScale)
+		// 字段数据全为 NULL 或者表无数据，无依据调整类型
+		if !st.hasValue {
+			return "", true
+		}
+		return narrowDecimalType(c, st), true
+	}
+	return "", true
+}
+
+// violations 汇总各字段校验类型越界数据行数、rowid 样本数以及首次、最后出现越界数据的 chunk
+// 字段判定后跳过剩余 chunk (exact-count=false) 时越界数据行数为下限值，标记 PartialCount
+func (s *columnState) violations(results []database.Scan, totalChunks int64) []database.Violation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sampleCounts := make(map[columnCheck]int64)
+	for _, r := range results {
+		sampleCounts[columnCheck{columnName: strings.ToUpper(r.ColumnName), checkType: strings.ToUpper(r.CheckType)}]++
+	}
+
+	var violations []database.Violation
+	for _, key := range s.keys {
+		st := s.stats[key]
+		if st.checkViolateCount(key.checkType) == 0 {
+			continue
+		}
+		violations = append(violations, database.Violation{
+			RunID:            st.firstChunk.RunID,
+			SchemaNameS:      st.firstChunk.SchemaNameS,
+			SchemaNameT:      st.firstChunk.SchemaNameT,
+			TableNameT:       st.firstChunk.TableNameT,
+			ColumnName:       key.columnName,
+			CheckType:        key.checkType,
+			ViolateCount:     st.violateCount,
+			SampleCount:      sampleCounts[key],
+			FirstChunkDetail: st.firstChunk.ChunkDetailT,
+			LastChunkDetail:  st.lastChunk.ChunkDetailT,
+			ScannedChunks:    st.chunkCount,
+			TotalChunks:      totalChunks,
+			PartialCount:     st.chunkCount < totalChunks,
+		})
+	}
+	return violations
+}
+
+// profiles 汇总各字段校验类型取值范围、NULL 以及非 NULL 行数、越界数据行数以及已扫描 chunk 数
+func (s *columnState) profiles(runID uint, schemaNameS, schemaNameT, tableName string) []database.Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var profiles []database.Profile
+	for _, key := range s.keys {
+		st := s.stats[key]
+		p := database.Profile{
+			RunID:            runID,
+			SchemaNameS:      schemaNameS,
+			SchemaNameT:      schemaNameT,
+			TableNameT:       tableName,
+			ColumnName:       key.columnName,
+			CheckType:        key.checkType,
+			NullCount:        st.nullCount,
+			NotNullCount:     st.notNullCount,
+			ViolateCount:     st.checkViolateCount(key.checkType),
+			MaxIntegerDigits: st.maxIntegerDigits,
+			MaxScale:         st.maxScale,
+			MaxLength:        st.maxLength,
+			MaxByteLength:    st.maxByteLength,
+			ChunkCount:       st.chunkCount,
+		}
+		switch {
+		case !st.hasValue:
+		case strings.EqualFold(key.checkType, database.CheckTypeTemporal):
+			p.MinValue, p.MaxValue = st.minTemporal, st.maxTemporal
+		default:
+			p.MinValue, p.MaxValue = st.minValue.String(), st.maxValue.String()
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles
+}
+
+// checkViolateCount 返回校验类型越界数据行数，整型校验逐行按 BIGINT 取值范围记录越界数据，
+// 字段数据均处于 [0, UNSIGNED BIGINT] 时建议无符号整型，按无符号取值范围不存在越界数据
+func (st columnStat) checkViolateCount(checkType string) int64 {
+	if strings.EqualFold(checkType, database.CheckTypeInteger) && st.hasValue &&
+		st.minValue.Sign() >= 0 && st.maxValue.Cmp(common.UnsignedBigintMax) <= 0 {
+		return 0
+	}
+	return st.violateCount
+}
+
+// widenDecimalType 扩大 decimal 整数位以及小数位容纳源端数据，超出 mysql decimal 精度上限返回 false
+func widenDecimalType(c database.CheckColumn, st columnStat) (string, bool) {
+	integerDigits, scale := c.Precision-c.Scale, c.Scale
+	if st.maxIntegerDigits > integerDigits {
+		integerDigits = st.maxIntegerDigits
+	}
+	if st.maxScale > scale {
+		scale = st.maxScale
+	}
+	return fitDecimalType(integerDigits, scale)
+}
+
+// fitDecimalType 返回容纳指定整数位以及小数位的 decimal，超出 mysql decimal 精度上限返回 false
+func fitDecimalType(integerDigits, scale int) (string, bool) {
+	if scale > common.MySQLDecimalMaxScale || integerDigits+scale > common.MySQLDecimalMaxPrecision {
+		return "", false
+	}
+	// decimal 精度最小为 1
+	if integerDigits+scale == 0 {
+		integerDigits = 1
+	}
+	return fmt.Sprintf("DECIMAL(%d,%d)", integerDigits+scale, scale), true
+}
+
+// narrowDecimalType 字段数据均为整数返回最窄整型，无可容纳整型返回小数位为 0 的 decimal，实际小数位小于定义小数位返回缩小小数位的 decimal，否则返回空字符串
+// 字段默认值同样需写入新字段类型，默认值参与小数位以及取值范围计算，默认值非数值时不缩小
+func narrowDecimalType(c database.CheckColumn, st columnStat) string {
+	maxScale, minValue, maxValue := st.maxScale, st.minValue, st.maxValue
+	if c.DataDefault != "" {
+		value, err := decimal.NewFromString(strings.Trim(c.DataDefault, "'"))
+		if err != nil {
+			return ""
+		}
+		// mysql 按定义小数位补齐默认值末尾 0，eg: decimal(20,2) default 0 记录为 0.00，小数位忽略末尾 0
+		if _, scale := common.DecimalDigits(value); scale > maxScale {
+			maxScale = scale
+		}
+		if value.Cmp(minValue) == -1 {
+			minValue = value
+		}
+		if value.Cmp(maxValue) == 1 {
+			maxValue = value
+		}
+	}
+	if maxScale == 0 {
+		if columnType := common.NarrowestIntegerType(minValue, maxValue); columnType != "" {
+			return columnType
+		}
+		return fmt.Sprintf("DECIMAL(%d,0)", c.Precision-c.Scale)
+	}
+	if maxScale < c.Scale {
+		return fmt.Sprintf("DECIMAL(%d,%d)", c.Precision-c.Scale+maxScale, maxScale)
+	}
+	return ""
+}
+
+// fitStringType 返回容纳指定字符长度以及字段字符集字节长度的字符类型，char 超出 255 字符改用 varchar，
+// varchar 声明长度或实际字节长度超出 65535 字节改用 mediumtext，实际字节长度超出 mediumtext 改用 longtext
+func fitStringType(c database.CheckColumn, length, byteLength int) string {
+	if strings.EqualFold(c.DataType, "CHAR") && length <= common.MySQLCharMaxLength {
+		return fmt.Sprintf("CHAR(%d)", length)
+	}
+	maxBytes, ok := common.MySQLCharsetMaxBytes[c.Charset]
+	if !ok {
+		maxBytes = common.MySQLCharsetMaxBytes[common.MYSQLCharsetUTF8MB4]
+	}
+	switch {
+	case byteLength > common.MySQLMediumTextMaxBytes:
+		return "LONGTEXT"
+	case byteLength > common.MySQLVarcharMaxBytes || length*maxBytes > common.MySQLVarcharMaxBytes:
+		return "MEDIUMTEXT"
+	}
+	return fmt.Sprintf("VARCHAR(%d)", length)
+}
+
+// temporalFitDatetime 字段时间取值范围是否处于 datetime 取值范围
+func temporalFitDatetime(st columnStat) bool {
+	return st.hasValue &&
+		common.CompareTemporal(st.minTemporal, common.MySQLDatetimeMin) >= 0 &&
+		common.CompareTemporal(st.maxTemporal, common.MySQLDatetimeMax) <= 0
+}
+
+// columnSample 限制表各字段校验类型越界数据 rowid 样本记录数
+type columnSample struct {
+	mu     sync.Mutex
+	limit  int
+	counts map[columnCheck]int
+}
+
+func newColumnSample(limit int) *columnSample {
+	return &columnSample{
+		limit:  limit,
+		counts: make(map[columnCheck]int),
+	}
+}
+
+// take 返回样本数未达上限的越界数据，并累计样本数
+func (s *columnSample) take(results []database.Scan) []database.Scan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var samples []database.Scan
+	for _, r := range results {
+		key := columnCheck{columnName: strings.ToUpper(r.ColumnName), checkType: strings.ToUpper(r.CheckType)}
+		if s.limit > 0 && s.counts[key] >= s.limit {
+			continue
+		}
+		s.counts[key]++
+		samples = append(samples, r)
+	}
+	return samples
+}
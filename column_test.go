@@ -0,0 +1,140 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"testing"
+
+	"github.com/greatcloak/decimal"
+	"github.com/wentaojin/scan/database"
+)
+
+func TestFitStringType(t *testing.T) {
+	cases := []struct {
+		name       string
+		column     database.CheckColumn
+		length     int
+		byteLength int
+		want       string
+	}{
+		{name: "char fit", column: database.CheckColumn{DataType: "CHAR", Charset: "UTF8MB4"}, length: 20, byteLength: 60, want: "CHAR(20)"},
+		{name: "char over 255", column: database.CheckColumn{DataType: "CHAR", Charset: "UTF8MB4"}, length: 300, byteLength: 300, want: "VARCHAR(300)"},
+		{name: "utf8mb4 varchar", column: database.CheckColumn{DataType: "VARCHAR", Charset: "UTF8MB4"}, length: 16383, byteLength: 16383, want: "VARCHAR(16383)"},
+		{name: "utf8mb4 declared over 65535", column: database.CheckColumn{DataType: "VARCHAR", Charset: "UTF8MB4"}, length: 16384, byteLength: 16384, want: "MEDIUMTEXT"},
+		{name: "latin1 varchar", column: database.CheckColumn{DataType: "VARCHAR", Charset: "LATIN1"}, length: 60000, byteLength: 60000, want: "VARCHAR(60000)"},
+		{name: "unknown charset bytes over 65535", column: database.CheckColumn{DataType: "VARCHAR", Charset: "UNKNOWN"}, length: 10000, byteLength: 70000, want: "MEDIUMTEXT"},
+		{name: "bytes over mediumtext", column: database.CheckColumn{DataType: "VARCHAR", Charset: "UTF8MB4"}, length: 5000000, byteLength: 20000000, want: "LONGTEXT"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := fitStringType(c.column, c.length, c.byteLength); got != c.want {
+				t.Errorf("fitStringType(%d, %d) = %q, want %q", c.length, c.byteLength, got, c.want)
+			}
+		})
+	}
+}
+
+func TestNarrowDecimalType(t *testing.T) {
+	column := database.CheckColumn{DataType: "DECIMAL", Precision: 20, Scale: 2}
+	stat := func(minValue, maxValue string, maxScale int) columnStat {
+		return columnStat{
+			minValue: decimal.RequireFromString(minValue),
+			maxValue: decimal.RequireFromString(maxValue),
+			hasValue: true,
+			maxScale: maxScale,
+		}
+	}
+	cases := []struct {
+		name        string
+		precision   int
+		dataDefault string
+		st          columnStat
+		want        string
+	}{
+		{name: "integer data", st: stat("1", "100", 0), want: "TINYINT(4)"},
+		{name: "integer default", dataDefault: "5", st: stat("1", "100", 0), want: "TINYINT(4)"},
+		{name: "default out of data range", dataDefault: "300", st: stat("1", "100", 0), want: "SMALLINT(6)"},
+		{name: "normalized zero default", dataDefault: "0.00", st: stat("1", "100", 0), want: "TINYINT(4)"},
+		{name: "normalized integer default", dataDefault: "300.00", st: stat("1", "100", 0), want: "SMALLINT(6)"},
+		{name: "fractional default", dataDefault: "1.50", st: stat("1", "100", 0), want: "DECIMAL(19,1)"},
+		{name: "fractional default quoted", dataDefault: "'1.5'", st: stat("1", "100", 0), want: "DECIMAL(19,1)"},
+		{name: "default at declared scale", dataDefault: "1.25", st: stat("1", "100", 0), want: ""},
+		{name: "integer beyond bigint", precision: 30, st: stat("-1", "99999999999999999999", 0), want: "DECIMAL(28,0)"},
+		{name: "default scale below data scale", dataDefault: "0.1", st: stat("0.01", "1.01", 1), want: "DECIMAL(19,1)"},
+		{name: "expression default", dataDefault: "(rand())", st: stat("1", "100", 0), want: ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			col := column
+			col.DataDefault = c.dataDefault
+			if c.precision > 0 {
+				col.Precision = c.precision
+			}
+			if got := narrowDecimalType(col, c.st); got != c.want {
+				t.Errorf("narrowDecimalType(default %q) = %q, want %q", c.dataDefault, got, c.want)
+			}
+		})
+	}
+}
+
+func TestColumnStateIntegerViolations(t *testing.T) {
+	summary := func(chunkID uint, minValue, maxValue string, violateCount int64) database.Summary {
+		return database.Summary{
+			ChunkID:      chunkID,
+			ColumnName:   "ID",
+			CheckType:    database.CheckTypeInteger,
+			NotNullCount: 10,
+			ViolateCount: violateCount,
+			MinValue:     minValue,
+			MaxValue:     maxValue,
+		}
+	}
+	cases := []struct {
+		name          string
+		summaries     []database.Summary
+		wantType      string
+		wantViolation int64
+	}{
+		{name: "fits signed bigint", summaries: []database.Summary{summary(1, "-5", "100", 0)}, wantType: "TINYINT(4)", wantViolation: 0},
+		{name: "fits unsigned bigint", summaries: []database.Summary{summary(1, "0", "100", 0), summary(2, "5", "18446744073709551615", 3)}, wantType: "BIGINT(20) UNSIGNED", wantViolation: 0},
+		{name: "negative and above bigint", summaries: []database.Summary{summary(1, "-1", "100", 0), summary(2, "5", "9223372036854775808", 2)}, wantType: "", wantViolation: 2},
+		{name: "above unsigned bigint", summaries: []database.Summary{summary(1, "0", "18446744073709551616", 4)}, wantType: "", wantViolation: 4},
+	}
+	column := database.CheckColumn{ColumnName: "ID", DataType: "DECIMAL", Precision: 20, Checks: []string{database.CheckTypeInteger}}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s := newColumnState()
+			if err := s.merge(c.summaries); err != nil {
+				t.Fatalf("merge() error: %v", err)
+			}
+			if got, _ := s.recommend(column); got != c.wantType {
+				t.Errorf("recommend() = %q, want %q", got, c.wantType)
+			}
+			var got int64
+			for _, v := range s.violations(nil, int64(len(c.summaries))) {
+				got += v.ViolateCount
+			}
+			if got != c.wantViolation {
+				t.Errorf("violations() count = %d, want %d", got, c.wantViolation)
+			}
+			for _, p := range s.profiles(1, "S", "T", "T_ORDER") {
+				if p.ViolateCount != c.wantViolation {
+					t.Errorf("profiles() violate count = %d, want %d", p.ViolateCount, c.wantViolation)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/scan/common"
+	"github.com/wentaojin/scan/config"
+	"github.com/wentaojin/scan/database"
+	"go.uber.org/zap"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// chunkStatus chunk 状态输出顺序
+var chunkStatus = []string{"WAITING", "RUNNING", "FAILED", "SKIPPED", "SUCCESS"}
+
+// parseTableName 解析 --table 参数，支持 源端schema.表名 以及 表名，未指定 schema 匹配全部 schema 映射同名表
+func parseTableName(tableName string) (string, string) {
+	if idx := strings.Index(tableName, "."); idx >= 0 {
+		return strings.ToUpper(tableName[:idx]), strings.ToUpper(tableName[idx+1:])
+	}
+	return "", strings.ToUpper(tableName)
+}
+
+// Status 输出运行信息以及 schema 或者指定表各状态 chunk 数
+func Status(ctx context.Context, dbM *database.Meta, cfg *config.Config) error {
+	run, err := database.NewRunModel(dbM).GetRun(ctx, cfg.SchemaDetail(), cfg.RunID)
+	if err != nil {
+		return err
+	}
+	schemaName, tableName := parseTableName(cfg.TableName)
+	status, err := database.NewFullModel(dbM).StatusFullSyncMeta(ctx, &database.Full{
+		RunID:       run.ID,
+		SchemaNameS: schemaName,
+		TableNameT:  tableName,
+	})
+	if err != nil {
+		return err
+	}
+
+	var tables []string
+	counts := make(map[string]map[string]int64)
+	for _, s := range status {
+		t := fmt.Sprintf("%s.%s\t%s", s.SchemaNameS, s.TableNameT, s.SchemaNameT)
+		if _, ok := counts[t]; !ok {
+			counts[t] = make(map[string]int64)
+			tables = append(tables, t)
+		}
+		counts[t][strings.ToUpper(s.TaskStatus)] += s.ChunkCount
+	}
+
+	endTime := "-"
+	if run.EndTime != nil {
+		endTime = run.EndTime.Format("2006-01-02 15:04:05")
+	}
+	fmt.Fprintf(os.Stdout, "RUN: %d  SCHEMA: %s  STATUS: %s  START: %s  END: %s\n",
+		run.ID, run.SchemaDetail, run.RunStatus, run.StartTime.Format("2006-01-02 15:04:05"), endTime)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "TABLE\tMYSQL SCHEMA\tTOTAL\t%s\n", strings.Join(chunkStatus, "\t"))
+	for _, t := range tables {
+		var (
+			total int64
+			cols  []string
+		)
+		for _, c := range counts[t] {
+			total += c
+		}
+		for _, s := range chunkStatus {
+			cols = append(cols, fmt.Sprintf("%d", counts[t][s]))
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", t, total, strings.Join(cols, "\t"))
+	}
+	return w.Flush()
+}
+
+// Reset 重置指定表全部 chunk 为 WAITING，并清理表扫描结果以及报告，scan 子命令重新扫描
+func Reset(ctx context.Context, dbM *database.Meta, cfg *config.Config) error {
+	sTime := time.Now()
+	schemaName, tableName := parseTableName(cfg.TableName)
+
+	run, err := database.NewRunModel(dbM).GetRun(ctx, cfg.SchemaDetail(), cfg.RunID)
+	if err != nil {
+		return err
+	}
+
+	chunks, err := database.NewFullModel(dbM).DetailFullSyncMeta(ctx, &database.Full{
+		RunID:       run.ID,
+		SchemaNameS: schemaName,
+		TableNameT:  tableName,
+	})
+	if err != nil {
+		return err
+	}
+	if len(chunks) == 0 {
+		return fmt.Errorf("table [%s] run [%d] chunk not found, please run split subcommand", cfg.TableName, run.ID)
+	}
+
+	// 未指定 schema 时重置全部 schema 映射同名表
+	resets := make(map[database.Full]int)
+	for _, c := range chunks {
+		resets[database.Full{RunID: c.RunID, SchemaNameS: c.SchemaNameS, SchemaNameT: c.SchemaNameT, TableNameT: c.TableNameT}]++
+	}
+
+	for r, n := range resets {
+		for _, t := range []string{"scan", "summary", "statistics", "violation", "profile"} {
+			err = dbM.DB(ctx).Table(common.QuoteMySQLTable(cfg.MetaConfig.MetaSchema, t)).
+				Where("run_id = ? AND schema_name_s = ? AND schema_name_t = ? AND table_name_t = ?", r.RunID, r.SchemaNameS, r.SchemaNameT, r.TableNameT).
+				Delete(map[string]interface{}{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = database.NewFullModel(dbM).UpdateFullSyncMetaTable(ctx, &r, map[string]interface{}{
+			"TaskStatus": "WAITING",
+		})
+		if err != nil {
+			return err
+		}
+		zap.L().Info("reset table chunk success", zap.String("schema", r.SchemaNameS), zap.String("mysql schema", r.SchemaNameT), zap.String("table", r.TableNameT), zap.Uint("run", run.ID), zap.Int("chunks", n))
+	}
+
+	zap.L().Info("reset table chunk finished", zap.String("table", cfg.TableName), zap.Uint("run", run.ID), zap.Int("chunks", len(chunks)), zap.String("cost", time.Now().Sub(sTime).String()))
+	return nil
+}
@@ -46,6 +46,7 @@ const (
 var (
 	MYSQLCharsetUTF8MB4       = "UTF8MB4"
 	MYSQLCharsetUTF8          = "UTF8"
+	MYSQLCharsetUTF8MB3       = "UTF8MB3"
 	MYSQLCharsetBIG5          = "BIG5"
 	MYSQLCharsetGBK           = "GBK"
 	MYSQLCharsetGB18030       = "GB18030"
@@ -65,11 +66,31 @@ var MigrateOracleCharsetStringConvertMapping = map[string]string{
 var MigrateMYSQLCompatibleCharsetStringConvertMapping = map[string]string{
 	MYSQLCharsetUTF8MB4: CharsetUTF8MB4,
 	MYSQLCharsetUTF8:    CharsetUTF8MB4,
+	MYSQLCharsetUTF8MB3: CharsetUTF8MB4,
 	MYSQLCharsetBIG5:    CharsetBIG5,
 	MYSQLCharsetGBK:     CharsetGBK,
 	MYSQLCharsetGB18030: CharsetGB18030,
 }
 
+// MySQLCharsetMaxBytes mysql 字符集单字符最大字节数
+var MySQLCharsetMaxBytes = map[string]int{
+	"UTF8MB4": 4,
+	"UTF8MB3": 3,
+	"UTF8":    3,
+	"GB18030": 4,
+	"GBK":     2,
+	"BIG5":    2,
+	"LATIN1":  1,
+	"ASCII":   1,
+	"BINARY":  1,
+}
+
+const (
+	MySQLCharMaxLength      = 255
+	MySQLVarcharMaxBytes    = 65535
+	MySQLMediumTextMaxBytes = 16777215
+)
+
 func StringsBuilder(str ...string) string {
 	var b strings.Builder
 	for _, p := range str {
@@ -0,0 +1,73 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import (
+	"strings"
+)
+
+// mysql 字符串字面量转义，单引号双写，sql_mode 启用 NO_BACKSLASH_ESCAPES 时同样不会提前结束字面量
+// 反斜杠转义为 \\，NO_BACKSLASH_ESCAPES 下仅多出反斜杠，其余控制字符原样输出
+var mysqlStringEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `''`,
+)
+
+// QuoteMySQLIdentifier mysql 标识符增加反引号，标识符内反引号双写
+func QuoteMySQLIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// QuoteMySQLTable 返回 `schema`.`table`
+func QuoteMySQLTable(schemaName, tableName string) string {
+	return QuoteMySQLIdentifier(schemaName) + "." + QuoteMySQLIdentifier(tableName)
+}
+
+// QuoteMySQLString mysql 字符串字面量增加单引号并转义
+func QuoteMySQLString(s string) string {
+	return "'" + mysqlStringEscaper.Replace(s) + "'"
+}
+
+// mysql 时间函数默认值
+var mysqlTemporalFunctions = []string{"CURRENT_TIMESTAMP", "NOW(", "LOCALTIME", "LOCALTIMESTAMP", "CURRENT_DATE", "CURRENT_TIME"}
+
+// MySQLDefaultValue 将 information_schema.COLUMNS COLUMN_DEFAULT 转换为 DEFAULT 子句取值
+// 时间函数以及 bit、十六进制字面量原样输出，EXTRA DEFAULT_GENERATED 的其余表达式默认值外层增加括号，其余按字符串字面量转义
+func MySQLDefaultValue(value, extra string) string {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	for _, fn := range mysqlTemporalFunctions {
+		if strings.HasPrefix(upper, fn) {
+			return value
+		}
+	}
+	if strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED") {
+		return "(" + value + ")"
+	}
+	if strings.HasPrefix(upper, "B'") || strings.HasPrefix(upper, "X'") || strings.HasPrefix(upper, "0X") {
+		return value
+	}
+	return QuoteMySQLString(value)
+}
+
+// QuoteOracleIdentifier oracle 标识符增加双引号，按名称大小写精确匹配 oracle 对象
+func QuoteOracleIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteOracleString oracle 字符串字面量增加单引号，单引号双写
+func QuoteOracleString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
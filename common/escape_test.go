@@ -0,0 +1,87 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import "testing"
+
+func TestQuoteMySQLIdentifier(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "T_ORDER", want: "`T_ORDER`"},
+		{name: "backtick", in: "a`b", want: "`a``b`"},
+		{name: "only backtick", in: "`", want: "````"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := QuoteMySQLIdentifier(c.in); got != c.want {
+				t.Errorf("QuoteMySQLIdentifier(%q) = %q, want %q", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestQuoteMySQLString(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain", in: "abc", want: `'abc'`},
+		{name: "single quote", in: "it's", want: `'it''s'`},
+		{name: "backslash", in: `a\b`, want: `'a\\b'`},
+		{name: "backslash before quote", in: `a\'b`, want: `'a\\''b'`},
+		{name: "trailing backslash", in: `a\`, want: `'a\\'`},
+		{name: "newline", in: "a\nb", want: "'a\nb'"},
+		{name: "nul", in: "a\x00b", want: "'a\x00b'"},
+		{name: "mixed", in: "'\\\n\x00", want: "'''\\\\\n\x00'"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := QuoteMySQLString(c.in); got != c.want {
+				t.Errorf("QuoteMySQLString(%q) = %q, want %q", c.in, got, c.want)
+			}
+		})
+	}
+}
+
+func TestMySQLDefaultValue(t *testing.T) {
+	cases := []struct {
+		name  string
+		value string
+		extra string
+		want  string
+	}{
+		{name: "current timestamp fsp", value: "CURRENT_TIMESTAMP(3)", extra: "DEFAULT_GENERATED", want: "CURRENT_TIMESTAMP(3)"},
+		{name: "current timestamp lower", value: "current_timestamp", extra: "", want: "current_timestamp"},
+		{name: "numeric literal", value: "0", extra: "", want: `'0'`},
+		{name: "quoted zero", value: "'0'", extra: "", want: `'''0'''`},
+		{name: "string literal quote", value: "a'b", extra: "", want: `'a''b'`},
+		{name: "default generated expression", value: "(now() + interval 1 day)", extra: "DEFAULT_GENERATED", want: "((now() + interval 1 day))"},
+		{name: "default generated function", value: "uuid()", extra: "DEFAULT_GENERATED", want: "(uuid())"},
+		{name: "bit literal", value: "b'1'", extra: "", want: "b'1'"},
+		{name: "hex literal", value: "0x616263", extra: "", want: "0x616263"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := MySQLDefaultValue(c.value, c.extra); got != c.want {
+				t.Errorf("MySQLDefaultValue(%q, %q) = %q, want %q", c.value, c.extra, got, c.want)
+			}
+		})
+	}
+}
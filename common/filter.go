@@ -0,0 +1,90 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// FilterRegexPrefix 正则匹配规则前缀，其余规则按 glob 匹配
+const FilterRegexPrefix = "regex:"
+
+// Filter 名称 include/exclude 过滤，忽略大小写，exclude 优先，未配置 include 匹配全部
+type Filter struct {
+	include []func(string) bool
+	exclude []func(string) bool
+}
+
+func NewFilter(include, exclude []string) (*Filter, error) {
+	f := &Filter{}
+	for _, p := range include {
+		m, err := newMatcher(p)
+		if err != nil {
+			return nil, err
+		}
+		f.include = append(f.include, m)
+	}
+	for _, p := range exclude {
+		m, err := newMatcher(p)
+		if err != nil {
+			return nil, err
+		}
+		f.exclude = append(f.exclude, m)
+	}
+	return f, nil
+}
+
+func (f *Filter) Match(name string) bool {
+	if f == nil {
+		return true
+	}
+	for _, m := range f.exclude {
+		if m(name) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, m := range f.include {
+		if m(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func newMatcher(pattern string) (func(string) bool, error) {
+	if strings.HasPrefix(strings.ToLower(pattern), FilterRegexPrefix) {
+		re, err := regexp.Compile("(?i)" + pattern[len(FilterRegexPrefix):])
+		if err != nil {
+			return nil, fmt.Errorf("filter pattern [%s] regex compile failed: %v", pattern, err)
+		}
+		return re.MatchString, nil
+	}
+
+	glob := strings.ToUpper(pattern)
+	if _, err := path.Match(glob, ""); err != nil {
+		return nil, fmt.Errorf("filter pattern [%s] glob parse failed: %v", pattern, err)
+	}
+	return func(name string) bool {
+		ok, _ := path.Match(glob, strings.ToUpper(name))
+		return ok
+	}, nil
+}
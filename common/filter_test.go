@@ -0,0 +1,86 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import "testing"
+
+func TestFilterMatch(t *testing.T) {
+	cases := []struct {
+		name    string
+		include []string
+		exclude []string
+		in      string
+		want    bool
+	}{
+		{name: "no rules match all", in: "T_ORDER", want: true},
+		{name: "glob include", include: []string{"T_*"}, in: "T_ORDER", want: true},
+		{name: "glob include miss", include: []string{"T_*"}, in: "ORDER", want: false},
+		{name: "glob case folding", include: []string{"t_ord?r"}, in: "T_ORDER", want: true},
+		{name: "glob name case folding", include: []string{"T_*"}, in: "t_order", want: true},
+		{name: "glob character class", include: []string{"T_[AB]*"}, in: "T_BILL", want: true},
+		{name: "glob whole name", include: []string{"ORDER"}, in: "T_ORDER", want: false},
+		{name: "regex include", include: []string{"regex:^T_\\d+$"}, in: "T_2024", want: true},
+		{name: "regex include miss", include: []string{"regex:^T_\\d+$"}, in: "T_ORDER", want: false},
+		{name: "regex case folding", include: []string{"regex:^t_order$"}, in: "T_ORDER", want: true},
+		{name: "regex prefix case folding", include: []string{"REGEX:^T_"}, in: "T_ORDER", want: true},
+		{name: "regex unanchored", include: []string{"regex:ORD"}, in: "T_ORDER", want: true},
+		{name: "regex star not glob", include: []string{"regex:^ORDER_*$"}, in: "ORDER", want: true},
+		{name: "glob star", include: []string{"ORDER_*"}, in: "ORDER", want: false},
+		{name: "exclude only", exclude: []string{"*_BAK"}, in: "T_ORDER_BAK", want: false},
+		{name: "exclude only miss", exclude: []string{"*_BAK"}, in: "T_ORDER", want: true},
+		{name: "exclude wins over include", include: []string{"T_*"}, exclude: []string{"*_BAK"}, in: "T_ORDER_BAK", want: false},
+		{name: "exclude regex wins over include glob", include: []string{"T_*"}, exclude: []string{"regex:_(BAK|TMP)$"}, in: "T_ORDER_TMP", want: false},
+		{name: "any include matches", include: []string{"A*", "T_*"}, in: "T_ORDER", want: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := NewFilter(c.include, c.exclude)
+			if err != nil {
+				t.Fatalf("NewFilter(%v, %v) error: %v", c.include, c.exclude, err)
+			}
+			if got := f.Match(c.in); got != c.want {
+				t.Errorf("Match(%q) with include %v exclude %v = %v, want %v", c.in, c.include, c.exclude, got, c.want)
+			}
+		})
+	}
+}
+
+func TestFilterNil(t *testing.T) {
+	var f *Filter
+	if !f.Match("T_ORDER") {
+		t.Errorf("nil filter Match() = false, want true")
+	}
+}
+
+func TestNewFilterInvalidPattern(t *testing.T) {
+	cases := []struct {
+		name    string
+		include []string
+		exclude []string
+	}{
+		{name: "bad glob include", include: []string{"T_[A"}},
+		{name: "bad glob exclude", exclude: []string{"T_[A"}},
+		{name: "bad regex include", include: []string{"regex:("}},
+		{name: "bad regex exclude", exclude: []string{"regex:[a-"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := NewFilter(c.include, c.exclude); err == nil {
+				t.Errorf("NewFilter(%v, %v) error = nil, want error", c.include, c.exclude)
+			}
+		})
+	}
+}
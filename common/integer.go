@@ -0,0 +1,72 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import (
+	"strings"
+
+	"github.com/greatcloak/decimal"
+)
+
+const (
+	MySQLDecimalMaxPrecision = 65
+	MySQLDecimalMaxScale     = 30
+)
+
+type IntegerType struct {
+	Name string
+	Min  decimal.Decimal
+	Max  decimal.Decimal
+}
+
+// MySQLIntegerTypes 按存储空间由小到大排列，相同存储空间优先有符号类型
+var MySQLIntegerTypes = []IntegerType{
+	{Name: "TINYINT(4)", Min: decimal.RequireFromString("-128"), Max: decimal.RequireFromString("127")},
+	{Name: "TINYINT(3) UNSIGNED", Min: decimal.Zero, Max: decimal.RequireFromString("255")},
+	{Name: "SMALLINT(6)", Min: decimal.RequireFromString("-32768"), Max: decimal.RequireFromString("32767")},
+	{Name: "SMALLINT(5) UNSIGNED", Min: decimal.Zero, Max: decimal.RequireFromString("65535")},
+	{Name: "MEDIUMINT(9)", Min: decimal.RequireFromString("-8388608"), Max: decimal.RequireFromString("8388607")},
+	{Name: "MEDIUMINT(8) UNSIGNED", Min: decimal.Zero, Max: decimal.RequireFromString("16777215")},
+	{Name: "INT(11)", Min: decimal.RequireFromString("-2147483648"), Max: decimal.RequireFromString("2147483647")},
+	{Name: "INT(10) UNSIGNED", Min: decimal.Zero, Max: decimal.RequireFromString("4294967295")},
+	{Name: "BIGINT(20)", Min: BigintMin, Max: BigintMax},
+	{Name: "BIGINT(20) UNSIGNED", Min: decimal.Zero, Max: UnsignedBigintMax},
+}
+
+// NarrowestIntegerType 返回可容纳 [min, max] 取值范围的最窄 mysql 整型，不存在则返回空字符串
+func NarrowestIntegerType(min, max decimal.Decimal) string {
+	for _, t := range MySQLIntegerTypes {
+		if min.Cmp(t.Min) >= 0 && max.Cmp(t.Max) <= 0 {
+			return t.Name
+		}
+	}
+	return ""
+}
+
+var (
+	BigintMin         = decimal.RequireFromString("-9223372036854775808")
+	BigintMax         = decimal.RequireFromString("9223372036854775807")
+	UnsignedBigintMax = decimal.RequireFromString("18446744073709551615")
+)
+
+// DecimalDigits 返回数值整数位数以及小数位数，忽略符号以及前导、末尾 0
+func DecimalDigits(value decimal.Decimal) (int, int) {
+	integerPart, fractionPart := value.Abs().String(), ""
+	if idx := strings.Index(integerPart, "."); idx >= 0 {
+		integerPart, fractionPart = integerPart[:idx], integerPart[idx+1:]
+	}
+	return len(strings.TrimLeft(integerPart, "0")), len(strings.TrimRight(fractionPart, "0"))
+}
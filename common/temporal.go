@@ -0,0 +1,73 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// mysql 时间类型取值范围，格式与 oracle TO_CHAR(..., 'SYYYY-MM-DD HH24:MI:SS.FF6') 一致
+// timestamp 取值范围以 UTC 计算，oracle 时间按 mysql time_zone 本地时间写入，校验前需经 MySQLTimestampRange 换算
+const (
+	MySQLDatetimeMin  = "1000-01-01 00:00:00.000000"
+	MySQLDatetimeMax  = "9999-12-31 23:59:59.999999"
+	MySQLTimestampMin = "1970-01-01 00:00:01.000000"
+	MySQLTimestampMax = "2038-01-19 03:14:07.999999"
+)
+
+// temporalLayout 与 TO_CHAR(..., 'SYYYY-MM-DD HH24:MI:SS.FF6') 正数年份格式一致
+const temporalLayout = "2006-01-02 15:04:05.000000"
+
+// MySQLTimestampRange 返回 mysql time_zone 相对 UTC 偏移 offset 秒时 timestamp 本地时间取值范围
+func MySQLTimestampRange(offset int64) (string, string) {
+	minValue, _ := time.Parse(temporalLayout, MySQLTimestampMin)
+	maxValue, _ := time.Parse(temporalLayout, MySQLTimestampMax)
+	shift := time.Duration(offset) * time.Second
+	return minValue.Add(shift).Format(temporalLayout), maxValue.Add(shift).Format(temporalLayout)
+}
+
+// CompareTemporal 比较 oracle TO_CHAR(..., 'SYYYY-MM-DD HH24:MI:SS.FF6') 格式时间，公元前年份带负号
+func CompareTemporal(a, b string) int {
+	negA, negB := strings.HasPrefix(a, "-"), strings.HasPrefix(b, "-")
+	switch {
+	case negA && !negB:
+		return -1
+	case !negA && negB:
+		return 1
+	case negA && negB && len(a) >= 5 && len(b) >= 5:
+		// 公元前年份越大时间越早，同年比较月日时分秒
+		if c := strings.Compare(a[1:5], b[1:5]); c != 0 {
+			return -c
+		}
+		return strings.Compare(a[5:], b[5:])
+	}
+	return strings.Compare(a, b)
+}
+
+// InTemporalRange 时间是否处于 [minValue, maxValue] 取值范围
+func InTemporalRange(value, minValue, maxValue string) bool {
+	return CompareTemporal(value, minValue) >= 0 && CompareTemporal(value, maxValue) <= 0
+}
+
+// MySQLDatetimeType 返回指定小数秒精度的 datetime
+func MySQLDatetimeType(fsp int) string {
+	if fsp > 0 {
+		return fmt.Sprintf("DATETIME(%d)", fsp)
+	}
+	return "DATETIME"
+}
@@ -0,0 +1,39 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import "testing"
+
+func TestMySQLTimestampRange(t *testing.T) {
+	cases := []struct {
+		name    string
+		offset  int64
+		wantMin string
+		wantMax string
+	}{
+		{name: "utc", offset: 0, wantMin: "1970-01-01 00:00:01.000000", wantMax: "2038-01-19 03:14:07.999999"},
+		{name: "+08:00", offset: 8 * 3600, wantMin: "1970-01-01 08:00:01.000000", wantMax: "2038-01-19 11:14:07.999999"},
+		{name: "-05:00", offset: -5 * 3600, wantMin: "1969-12-31 19:00:01.000000", wantMax: "2038-01-18 22:14:07.999999"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			gotMin, gotMax := MySQLTimestampRange(c.offset)
+			if gotMin != c.wantMin || gotMax != c.wantMax {
+				t.Errorf("MySQLTimestampRange(%d) = [%q, %q], want [%q, %q]", c.offset, gotMin, gotMax, c.wantMin, c.wantMax)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TiDB 6.2 起支持单条 ALTER TABLE 多个 MODIFY 子句 (multi-schema change)
+const (
+	TiDBMultiSchemaChangeMajor = 6
+	TiDBMultiSchemaChangeMinor = 2
+)
+
+// SupportMultiSchemaChange 根据 SELECT VERSION() 判断是否支持单条 ALTER TABLE 多个子句
+// mysql 均支持，tidb 版本格式 eg: 5.7.25-TiDB-v6.5.0，无法解析时按不支持处理
+func SupportMultiSchemaChange(version string) bool {
+	upper := strings.ToUpper(version)
+	idx := strings.Index(upper, "TIDB-V")
+	if idx < 0 {
+		return !strings.Contains(upper, "TIDB")
+	}
+	parts := strings.SplitN(upper[idx+len("TIDB-V"):], ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	minor, err := strconv.Atoi(strings.TrimFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' }))
+	if err != nil {
+		return false
+	}
+	return major > TiDBMultiSchemaChangeMajor || (major == TiDBMultiSchemaChangeMajor && minor >= TiDBMultiSchemaChangeMinor)
+}
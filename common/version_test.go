@@ -0,0 +1,48 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import "testing"
+
+func TestSupportMultiSchemaChange(t *testing.T) {
+	cases := []struct {
+		name    string
+		version string
+		want    bool
+	}{
+		{name: "tidb 6.1 below cutoff", version: "5.7.25-TiDB-v6.1.7", want: false},
+		{name: "tidb 5.4 below cutoff", version: "5.7.25-TiDB-v5.4.3", want: false},
+		{name: "tidb 6.2 at cutoff", version: "5.7.25-TiDB-v6.2.0", want: true},
+		{name: "tidb 6.5 above cutoff", version: "5.7.25-TiDB-v6.5.0", want: true},
+		{name: "tidb 7.1 major above cutoff", version: "8.0.11-TiDB-v7.1.0", want: true},
+		{name: "tidb prerelease at cutoff", version: "5.7.25-TiDB-v6.2-alpha", want: true},
+		{name: "tidb lower case", version: "5.7.25-tidb-v6.2.0", want: true},
+		{name: "mysql 5.7", version: "5.7.44-log", want: true},
+		{name: "mysql 8.0", version: "8.0.35", want: true},
+		{name: "mariadb", version: "10.6.16-MariaDB-1:10.6.16+maria~ubu2004", want: true},
+		{name: "tidb without version", version: "5.7.25-TiDB", want: false},
+		{name: "tidb malformed major", version: "5.7.25-TiDB-vX.2.0", want: false},
+		{name: "tidb malformed minor", version: "5.7.25-TiDB-v6.x", want: false},
+		{name: "tidb missing minor", version: "5.7.25-TiDB-v6", want: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := SupportMultiSchemaChange(c.version); got != c.want {
+				t.Errorf("SupportMultiSchemaChange(%q) = %v, want %v", c.version, got, c.want)
+			}
+		})
+	}
+}
@@ -17,38 +17,78 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/BurntSushi/toml"
+	"github.com/wentaojin/scan/common"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 程序子命令，未指定子命令依次执行 init、split、scan、report
+const (
+	CommandInit   = "init"
+	CommandSplit  = "split"
+	CommandScan   = "scan"
+	CommandReport = "report"
+	CommandStatus = "status"
+	CommandReset  = "reset"
+	CommandPlan   = "plan"
+)
+
+var commands = []string{CommandInit, CommandSplit, CommandScan, CommandReport, CommandStatus, CommandReset, CommandPlan}
+
+// 支持的字段校验类型、扫描模式以及名称大小写规则
+var (
+	checkTypes = []string{"integer", "decimal", "scale", "float", "string", "temporal"}
+	scanModes  = []string{"row", "aggregate"}
+	alterModes = []string{AlterModeAuto, AlterModeTable, AlterModeColumn}
+	nameCases  = []string{NameCaseUpper, NameCaseLower, NameCasePreserve}
 )
 
 // 程序配置文件
 type Config struct {
 	*flag.FlagSet `json:"-"`
-	AppConfig     AppConfig    `toml:"app" json:"app"`
-	OracleConfig  OracleConfig `toml:"oracle" json:"oracle"`
-	MySQLConfig   MySQLConfig  `toml:"mysql" json:"mysql"`
-	MetaConfig    MetaConfig   `toml:"meta" json:"meta"`
-	LogConfig     LogConfig    `toml:"log" json:"log"`
-	ConfigFile    string       `json:"config-file"`
+	AppConfig     AppConfig     `toml:"app" json:"app"`
+	OracleConfig  OracleConfig  `toml:"oracle" json:"oracle"`
+	MySQLConfig   MySQLConfig   `toml:"mysql" json:"mysql"`
+	MetaConfig    MetaConfig    `toml:"meta" json:"meta"`
+	FilterConfig  FilterConfig  `toml:"filter" json:"filter"`
+	SchemaConfig  []SchemaPair  `toml:"schema" json:"schema"`
+	MappingConfig MappingConfig `toml:"mapping" json:"mapping"`
+	LogConfig     LogConfig     `toml:"log" json:"log"`
+	ConfigFile    string        `json:"config-file"`
+	Command       string        `json:"command"`
+	TableName     string        `json:"table"`
+	RunID         uint          `json:"run-id"`
 }
 
 type AppConfig struct {
-	BatchSize   int    `toml:"batch-size" json:"batch-size"`
-	InitThread  int    `toml:"init-thread" json:"init-thread"`
-	TableThread int    `toml:"table-thread" json:"table-thread"`
-	SQLThread   int    `toml:"sql-thread" json:"sql-thread"`
-	ChunkSize   int    `toml:"chunk-size" json:"chunk-size"`
-	SQLHint     string `toml:"sql-hint" json:"sql-hint"`
-	CallTimeout int64  `toml:"call-timeout" json:"call-timeout"`
-	SkipInit    bool   `toml:"skip-init" json:"skip-init"`
-	SkipSplit   bool   `toml:"skip-split" json:"skip-split"`
+	BatchSize     int      `toml:"batch-size" json:"batch-size"`
+	InitThread    int      `toml:"init-thread" json:"init-thread"`
+	TableThread   int      `toml:"table-thread" json:"table-thread"`
+	SQLThread     int      `toml:"sql-thread" json:"sql-thread"`
+	ChunkSize     int      `toml:"chunk-size" json:"chunk-size"`
+	SQLHint       string   `toml:"sql-hint" json:"sql-hint"`
+	ScanMode      string   `toml:"scan-mode" json:"scan-mode"`
+	AlterMode     string   `toml:"alter-mode" json:"alter-mode"`
+	CheckTypes    []string `toml:"check-types" json:"check-types"`
+	MaxSampleRows int      `toml:"max-sample-rows" json:"max-sample-rows"`
+	ExactCount    bool     `toml:"exact-count" json:"exact-count"`
+	EstimateRows  int      `toml:"estimate-rows-per-second" json:"estimate-rows-per-second"`
+	CallTimeout   int64    `toml:"call-timeout" json:"call-timeout"`
+	SkipInit      bool     `toml:"skip-init" json:"skip-init"`
+	SkipSplit     bool     `toml:"skip-split" json:"skip-split"`
 }
 
 type OracleConfig struct {
 	Username      string   `toml:"username" json:"username"`
-	Password      string   `toml:"password" json:"password"`
+	Password      Secret   `toml:"password" json:"password"`
+	PasswordEnv   string   `toml:"password-env" json:"password-env"`
+	PasswordFile  string   `toml:"password-file" json:"password-file"`
 	Host          string   `toml:"host" json:"host"`
 	Port          int      `toml:"port" json:"port"`
 	ServiceName   string   `toml:"service-name" json:"service-name"`
@@ -61,7 +101,9 @@ type OracleConfig struct {
 
 type MySQLConfig struct {
 	Username      string `toml:"username" json:"username"`
-	Password      string `toml:"password" json:"password"`
+	Password      Secret `toml:"password" json:"password"`
+	PasswordEnv   string `toml:"password-env" json:"password-env"`
+	PasswordFile  string `toml:"password-file" json:"password-file"`
 	Host          string `toml:"host" json:"host"`
 	Port          int    `toml:"port" json:"port"`
 	Charset       string `toml:"charset" json:"charset"`
@@ -71,13 +113,221 @@ type MySQLConfig struct {
 
 type MetaConfig struct {
 	Username      string `toml:"username" json:"username"`
-	Password      string `toml:"password" json:"password"`
+	Password      Secret `toml:"password" json:"password"`
+	PasswordEnv   string `toml:"password-env" json:"password-env"`
+	PasswordFile  string `toml:"password-file" json:"password-file"`
 	Host          string `toml:"host" json:"host"`
 	Port          int    `toml:"port" json:"port"`
 	SlowThreshold int    `toml:"slow-threshold" json:"slow-threshold"`
 	MetaSchema    string `toml:"meta-schema" json:"meta-schema"`
 }
 
+// Secret 密码等敏感配置，序列化以及格式化输出时脱敏
+type Secret string
+
+const redacted = "******"
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// Reveal 返回明文，仅用于建立数据库连接
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// resolvePassword 密码来源优先级: password-file > password-env > password
+func resolvePassword(section string, password *Secret, passwordEnv, passwordFile string) error {
+	switch {
+	case passwordFile != "":
+		b, err := os.ReadFile(passwordFile)
+		if err != nil {
+			return fmt.Errorf("config [%s] password-file [%s] read failed: %v", section, passwordFile, err)
+		}
+		*password = Secret(strings.TrimRight(string(b), "\r\n"))
+	case passwordEnv != "":
+		v, ok := os.LookupEnv(passwordEnv)
+		if !ok {
+			return fmt.Errorf("config [%s] password-env [%s] isn't set", section, passwordEnv)
+		}
+		*password = Secret(v)
+	}
+	return nil
+}
+
+// SchemaPair 源端 oracle schema 与目标端 mysql schema 映射
+type SchemaPair struct {
+	OracleSchema string `toml:"oracle-schema" json:"oracle-schema"`
+	MySQLSchema  string `toml:"mysql-schema" json:"mysql-schema"`
+}
+
+// SchemaPairs 返回待扫描 schema 映射，未配置 [[schema]] 沿用 oracle、mysql schema 配置
+func (c *Config) SchemaPairs() []SchemaPair {
+	var pairs []SchemaPair
+	for _, p := range c.SchemaConfig {
+		pairs = append(pairs, SchemaPair{
+			OracleSchema: strings.ToUpper(p.OracleSchema),
+			MySQLSchema:  strings.ToUpper(p.MySQLSchema),
+		})
+	}
+	if len(pairs) == 0 {
+		pairs = append(pairs, SchemaPair{
+			OracleSchema: strings.ToUpper(c.OracleConfig.Schema),
+			MySQLSchema:  strings.ToUpper(c.MySQLConfig.Schema),
+		})
+	}
+	return pairs
+}
+
+// SchemaDetail 返回 schema 映射描述，eg: ORA1:MYSQL1,ORA2:MYSQL2，用于区分不同任务运行记录
+func (c *Config) SchemaDetail() string {
+	var details []string
+	for _, p := range c.SchemaPairs() {
+		details = append(details, p.OracleSchema+":"+p.MySQLSchema)
+	}
+	return strings.Join(details, ",")
+}
+
+// FilterConfig 表以及字段过滤规则，支持 glob 以及 regex: 前缀正则，忽略大小写，exclude 优先
+// 字段规则按 表名.字段名 匹配，不包含 . 的 glob 规则匹配全部表同名字段
+type FilterConfig struct {
+	IncludeTables  []string `toml:"include-tables" json:"include-tables"`
+	ExcludeTables  []string `toml:"exclude-tables" json:"exclude-tables"`
+	IncludeColumns []string `toml:"include-columns" json:"include-columns"`
+	ExcludeColumns []string `toml:"exclude-columns" json:"exclude-columns"`
+
+	tableFilter  *common.Filter
+	columnFilter *common.Filter
+}
+
+func (f *FilterConfig) compile() error {
+	var err error
+	if f.tableFilter, err = common.NewFilter(f.IncludeTables, f.ExcludeTables); err != nil {
+		return err
+	}
+	if f.columnFilter, err = common.NewFilter(columnPatterns(f.IncludeColumns), columnPatterns(f.ExcludeColumns)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func columnPatterns(patterns []string) []string {
+	var results []string
+	for _, p := range patterns {
+		if !strings.HasPrefix(strings.ToLower(p), common.FilterRegexPrefix) && !strings.Contains(p, ".") {
+			p = "*." + p
+		}
+		results = append(results, p)
+	}
+	return results
+}
+
+// MatchTable 表是否满足过滤规则
+func (f *FilterConfig) MatchTable(tableName string) bool {
+	return f.tableFilter.Match(tableName)
+}
+
+// MatchColumn 字段是否满足过滤规则
+func (f *FilterConfig) MatchColumn(tableName, columnName string) bool {
+	return f.columnFilter.Match(tableName + "." + columnName)
+}
+
+// 修改字段语句生成方式，auto 根据目标端版本自动选择，mysql 以及 tidb 6.2 及以上每张表合并为单条 ALTER TABLE
+const (
+	AlterModeAuto   = "auto"
+	AlterModeTable  = "table"
+	AlterModeColumn = "column"
+)
+
+// 名称大小写规则
+const (
+	NameCaseUpper    = "upper"
+	NameCaseLower    = "lower"
+	NameCasePreserve = "preserve"
+)
+
+// MappingConfig 目标端 mysql 表名、字段名映射为源端 oracle 表名、字段名规则
+// 依次应用显式映射、前缀以及后缀改写，最后应用大小写规则，显式映射以及改写规则忽略大小写匹配
+type MappingConfig struct {
+	Tables       map[string]string `toml:"tables" json:"tables"`
+	Columns      map[string]string `toml:"columns" json:"columns"`
+	TablePrefix  []RewriteRule     `toml:"table-prefix" json:"table-prefix"`
+	TableSuffix  []RewriteRule     `toml:"table-suffix" json:"table-suffix"`
+	ColumnPrefix []RewriteRule     `toml:"column-prefix" json:"column-prefix"`
+	ColumnSuffix []RewriteRule     `toml:"column-suffix" json:"column-suffix"`
+	TableCase    string            `toml:"table-case" json:"table-case"`
+	ColumnCase   string            `toml:"column-case" json:"column-case"`
+}
+
+// RewriteRule mysql 名称前缀或者后缀改写为 oracle 名称前缀或者后缀
+type RewriteRule struct {
+	MySQL  string `toml:"mysql" json:"mysql"`
+	Oracle string `toml:"oracle" json:"oracle"`
+}
+
+// OracleTableName 返回 mysql 表对应的 oracle 表名
+func (m *MappingConfig) OracleTableName(tableName string) string {
+	if name, ok := lookupName(m.Tables, tableName); ok {
+		return applyCase(name, m.TableCase)
+	}
+	return applyCase(rewriteName(tableName, m.TablePrefix, m.TableSuffix), m.TableCase)
+}
+
+// OracleColumnName 返回 mysql 表字段对应的 oracle 字段名，显式映射支持 表名.字段名 以及 字段名，表名.字段名 优先
+func (m *MappingConfig) OracleColumnName(tableName, columnName string) string {
+	if name, ok := lookupName(m.Columns, tableName+"."+columnName); ok {
+		return applyCase(name, m.ColumnCase)
+	}
+	if name, ok := lookupName(m.Columns, columnName); ok {
+		return applyCase(name, m.ColumnCase)
+	}
+	return applyCase(rewriteName(columnName, m.ColumnPrefix, m.ColumnSuffix), m.ColumnCase)
+}
+
+func lookupName(names map[string]string, name string) (string, bool) {
+	for k, v := range names {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func rewriteName(name string, prefix, suffix []RewriteRule) string {
+	for _, r := range prefix {
+		if len(name) >= len(r.MySQL) && strings.EqualFold(name[:len(r.MySQL)], r.MySQL) {
+			name = r.Oracle + name[len(r.MySQL):]
+			break
+		}
+	}
+	for _, r := range suffix {
+		if len(name) >= len(r.MySQL) && strings.EqualFold(name[len(name)-len(r.MySQL):], r.MySQL) {
+			name = name[:len(name)-len(r.MySQL)] + r.Oracle
+			break
+		}
+	}
+	return name
+}
+
+// applyCase 未配置大小写规则默认转换为大写，与 oracle 非引号标识符一致
+func applyCase(name, nameCase string) string {
+	switch strings.ToLower(nameCase) {
+	case NameCaseLower:
+		return strings.ToLower(name)
+	case NameCasePreserve:
+		return name
+	default:
+		return strings.ToUpper(name)
+	}
+}
+
 type LogConfig struct {
 	LogLevel   string `toml:"log-level" json:"log-level"`
 	LogFile    string `toml:"log-file" json:"log-file"`
@@ -91,15 +341,65 @@ func NewConfig() *Config {
 	cfg.FlagSet = flag.NewFlagSet("transferdb", flag.ContinueOnError)
 	fs := cfg.FlagSet
 	fs.Usage = func() {
-		fmt.Fprintln(os.Stderr, "Usage of transferdb:")
+		fmt.Fprintf(os.Stderr, "Usage of transferdb: transferdb [%s] [flags]\n", strings.Join(commands, "|"))
 		fs.
 			PrintDefaults()
 	}
 	fs.StringVar(&cfg.ConfigFile, "config", "./config.toml", "path to the configuration file")
+	fs.StringVar(&cfg.TableName, "table", "", "table name ([oracle_schema.]table) for status/reset subcommand, reset subcommand required")
+	fs.UintVar(&cfg.RunID, "run-id", 0, "run id for scan/report/status/reset subcommand, default latest run")
+	// 数据库连接命令行参数，指定后覆盖配置文件
+	for _, section := range []string{"oracle", "mysql", "meta"} {
+		fs.String(section+"-username", "", fmt.Sprintf("%s username, override config file", section))
+		fs.String(section+"-password", "", fmt.Sprintf("%s password, override config file, prefer password-env or password-file to keep it out of the process list", section))
+		fs.String(section+"-host", "", fmt.Sprintf("%s host, override config file", section))
+		fs.Int(section+"-port", 0, fmt.Sprintf("%s port, override config file", section))
+	}
 	return cfg
 }
 
 func (c *Config) Parse(args []string) error {
+	c.parseFlags(args)
+
+	// 子命令为首个非 flag 参数，可位于 flag 之前或者之后，子命令之后的 flag 继续解析
+	if rest := c.FlagSet.Args(); len(rest) > 0 {
+		c.Command = strings.ToLower(rest[0])
+		c.parseFlags(rest[1:])
+	}
+	if rest := c.FlagSet.Args(); len(rest) > 0 {
+		return fmt.Errorf("unexpected arguments [%s], only one subcommand is allowed", strings.Join(rest, " "))
+	}
+
+	if c.Command != "" {
+		var valid bool
+		for _, cmd := range commands {
+			if c.Command == cmd {
+				valid = true
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown subcommand [%s], support subcommand [%s]", c.Command, strings.Join(commands, ","))
+		}
+	}
+	if c.Command == CommandReset && c.TableName == "" {
+		return fmt.Errorf("subcommand [%s] requires flag --table", CommandReset)
+	}
+
+	if c.ConfigFile != "" {
+		if err := c.configFromFile(c.ConfigFile); err != nil {
+			return err
+		}
+	} else {
+		return fmt.Errorf("no config file")
+	}
+
+	if err := c.overrideFromFlags(); err != nil {
+		return err
+	}
+	return errors.Join(c.resolvePasswords(), c.Validate())
+}
+
+func (c *Config) parseFlags(args []string) {
 	err := c.FlagSet.Parse(args)
 	switch err {
 	case nil:
@@ -108,16 +408,155 @@ func (c *Config) Parse(args []string) error {
 	default:
 		os.Exit(2)
 	}
+}
 
-	if c.ConfigFile != "" {
-		if err = c.configFromFile(c.ConfigFile); err != nil {
-			return err
+// overrideFromFlags 命令行指定的数据库连接参数覆盖配置文件，指定密码时忽略 password-env、password-file
+func (c *Config) overrideFromFlags() error {
+	var err error
+	c.FlagSet.Visit(func(f *flag.Flag) {
+		value := f.Value.String()
+		switch f.Name {
+		case "oracle-username":
+			c.OracleConfig.Username = value
+		case "oracle-password":
+			c.OracleConfig.Password, c.OracleConfig.PasswordEnv, c.OracleConfig.PasswordFile = Secret(value), "", ""
+		case "oracle-host":
+			c.OracleConfig.Host = value
+		case "oracle-port":
+			c.OracleConfig.Port, err = strconv.Atoi(value)
+		case "mysql-username":
+			c.MySQLConfig.Username = value
+		case "mysql-password":
+			c.MySQLConfig.Password, c.MySQLConfig.PasswordEnv, c.MySQLConfig.PasswordFile = Secret(value), "", ""
+		case "mysql-host":
+			c.MySQLConfig.Host = value
+		case "mysql-port":
+			c.MySQLConfig.Port, err = strconv.Atoi(value)
+		case "meta-username":
+			c.MetaConfig.Username = value
+		case "meta-password":
+			c.MetaConfig.Password, c.MetaConfig.PasswordEnv, c.MetaConfig.PasswordFile = Secret(value), "", ""
+		case "meta-host":
+			c.MetaConfig.Host = value
+		case "meta-port":
+			c.MetaConfig.Port, err = strconv.Atoi(value)
+		}
+	})
+	return err
+}
+
+// resolvePasswords 按 password-env、password-file 配置读取各数据库密码
+func (c *Config) resolvePasswords() error {
+	return errors.Join(
+		resolvePassword("oracle", &c.OracleConfig.Password, c.OracleConfig.PasswordEnv, c.OracleConfig.PasswordFile),
+		resolvePassword("mysql", &c.MySQLConfig.Password, c.MySQLConfig.PasswordEnv, c.MySQLConfig.PasswordFile),
+		resolvePassword("meta", &c.MetaConfig.Password, c.MetaConfig.PasswordEnv, c.MetaConfig.PasswordFile),
+	)
+}
+
+// Validate 校验配置必填项、取值范围以及字符集，汇总全部错误一并返回
+func (c *Config) Validate() error {
+	var errs []error
+	required := func(section, name, value string) {
+		if strings.TrimSpace(value) == "" {
+			errs = append(errs, fmt.Errorf("config [%s] %s is required", section, name))
 		}
-	} else {
-		return fmt.Errorf("no config file")
+	}
+	positive := func(section, name string, value int64) {
+		if value <= 0 {
+			errs = append(errs, fmt.Errorf("config [%s] %s [%d] must be greater than 0", section, name, value))
+		}
+	}
+	nonNegative := func(section, name string, value int64) {
+		if value < 0 {
+			errs = append(errs, fmt.Errorf("config [%s] %s [%d] can't be less than 0", section, name, value))
+		}
+	}
+	oneOf := func(section, name, value string, values []string) {
+		for _, v := range values {
+			if strings.EqualFold(value, v) {
+				return
+			}
+		}
+		errs = append(errs, fmt.Errorf("config [%s] %s [%s] isn't support, support [%s]", section, name, value, strings.Join(values, ",")))
 	}
 
-	return nil
+	positive("app", "batch-size", int64(c.AppConfig.BatchSize))
+	positive("app", "init-thread", int64(c.AppConfig.InitThread))
+	positive("app", "table-thread", int64(c.AppConfig.TableThread))
+	positive("app", "sql-thread", int64(c.AppConfig.SQLThread))
+	positive("app", "chunk-size", int64(c.AppConfig.ChunkSize))
+	positive("app", "call-timeout", c.AppConfig.CallTimeout)
+	nonNegative("app", "max-sample-rows", int64(c.AppConfig.MaxSampleRows))
+	nonNegative("app", "estimate-rows-per-second", int64(c.AppConfig.EstimateRows))
+	if c.AppConfig.ScanMode != "" {
+		oneOf("app", "scan-mode", c.AppConfig.ScanMode, scanModes)
+	}
+	if c.AppConfig.AlterMode != "" {
+		oneOf("app", "alter-mode", c.AppConfig.AlterMode, alterModes)
+	}
+	for _, t := range c.AppConfig.CheckTypes {
+		oneOf("app", "check-types", t, checkTypes)
+	}
+
+	required("oracle", "username", c.OracleConfig.Username)
+	required("oracle", "host", c.OracleConfig.Host)
+	positive("oracle", "port", int64(c.OracleConfig.Port))
+	required("oracle", "service-name", c.OracleConfig.ServiceName)
+	if _, ok := common.MigrateOracleCharsetStringConvertMapping[strings.ToUpper(c.OracleConfig.Charset)]; !ok {
+		errs = append(errs, fmt.Errorf("config [oracle] charset [%s] isn't support, support [%s]", c.OracleConfig.Charset, strings.Join(mapKeys(common.MigrateOracleCharsetStringConvertMapping), ",")))
+	}
+
+	required("mysql", "username", c.MySQLConfig.Username)
+	required("mysql", "host", c.MySQLConfig.Host)
+	positive("mysql", "port", int64(c.MySQLConfig.Port))
+	if _, ok := common.MigrateMYSQLCompatibleCharsetStringConvertMapping[strings.ToUpper(c.MySQLConfig.Charset)]; !ok {
+		errs = append(errs, fmt.Errorf("config [mysql] charset [%s] isn't support, support [%s]", c.MySQLConfig.Charset, strings.Join(mapKeys(common.MigrateMYSQLCompatibleCharsetStringConvertMapping), ",")))
+	}
+
+	required("meta", "username", c.MetaConfig.Username)
+	required("meta", "host", c.MetaConfig.Host)
+	positive("meta", "port", int64(c.MetaConfig.Port))
+	required("meta", "meta-schema", c.MetaConfig.MetaSchema)
+
+	// 未配置 [[schema]] 时 oracle、mysql schema 必填，schema 映射不可重复
+	if len(c.SchemaConfig) == 0 {
+		required("oracle", "schema", c.OracleConfig.Schema)
+		required("mysql", "schema", c.MySQLConfig.Schema)
+	}
+	pairs := make(map[SchemaPair]struct{})
+	for i, p := range c.SchemaConfig {
+		required("schema", fmt.Sprintf("#%d oracle-schema", i+1), p.OracleSchema)
+		required("schema", fmt.Sprintf("#%d mysql-schema", i+1), p.MySQLSchema)
+	}
+	for _, p := range c.SchemaPairs() {
+		if _, ok := pairs[p]; ok {
+			errs = append(errs, fmt.Errorf("config [schema] oracle-schema [%s] mysql-schema [%s] is duplicate", p.OracleSchema, p.MySQLSchema))
+		}
+		pairs[p] = struct{}{}
+	}
+
+	if c.MappingConfig.TableCase != "" {
+		oneOf("mapping", "table-case", c.MappingConfig.TableCase, nameCases)
+	}
+	if c.MappingConfig.ColumnCase != "" {
+		oneOf("mapping", "column-case", c.MappingConfig.ColumnCase, nameCases)
+	}
+
+	if err := c.FilterConfig.compile(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func mapKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, strings.ToLower(k))
+	}
+	sort.Strings(keys)
+	return keys
 }
 
 // 加载配置文件并解析
@@ -128,6 +567,7 @@ func (c *Config) configFromFile(file string) error {
 	return nil
 }
 
+// String 返回 json 格式配置，密码脱敏
 func (c *Config) String() string {
 	cfg, err := json.Marshal(c)
 	if err != nil {
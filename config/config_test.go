@@ -0,0 +1,365 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFilterConfigMatchColumn(t *testing.T) {
+	cases := []struct {
+		name    string
+		include []string
+		exclude []string
+		table   string
+		column  string
+		want    bool
+	}{
+		{name: "no rules match all", table: "T_ORDER", column: "AMOUNT", want: true},
+		{name: "bare column glob matches any table", include: []string{"AMT*"}, table: "T_ORDER", column: "AMT_TOTAL", want: true},
+		{name: "bare column glob miss", include: []string{"AMT*"}, table: "T_ORDER", column: "PRICE", want: false},
+		{name: "bare column glob not table name", include: []string{"T_ORDER"}, table: "T_ORDER", column: "PRICE", want: false},
+		{name: "table qualified glob", include: []string{"T_ORDER.AMT*"}, table: "T_ORDER", column: "AMT_TOTAL", want: true},
+		{name: "table qualified glob other table", include: []string{"T_ORDER.AMT*"}, table: "T_BILL", column: "AMT_TOTAL", want: false},
+		{name: "table glob qualified", include: []string{"T_*.ID"}, table: "t_bill", column: "id", want: true},
+		{name: "case folding", include: []string{"t_order.amount"}, table: "T_ORDER", column: "AMOUNT", want: true},
+		{name: "regex matches qualified name", include: []string{"regex:^T_ORDER\\.AMT"}, table: "T_ORDER", column: "AMT_TOTAL", want: true},
+		{name: "regex not prefixed with table glob", include: []string{"regex:^AMT"}, table: "T_ORDER", column: "AMT_TOTAL", want: false},
+		{name: "exclude bare column wins", include: []string{"T_ORDER.*"}, exclude: []string{"*_BAK"}, table: "T_ORDER", column: "AMT_BAK", want: false},
+		{name: "exclude qualified keeps other tables", exclude: []string{"T_ORDER.AMT"}, table: "T_BILL", column: "AMT", want: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := FilterConfig{IncludeColumns: c.include, ExcludeColumns: c.exclude}
+			if err := f.compile(); err != nil {
+				t.Fatalf("compile() error: %v", err)
+			}
+			if got := f.MatchColumn(c.table, c.column); got != c.want {
+				t.Errorf("MatchColumn(%q, %q) with include %v exclude %v = %v, want %v", c.table, c.column, c.include, c.exclude, got, c.want)
+			}
+		})
+	}
+}
+
+func TestFilterConfigMatchTable(t *testing.T) {
+	f := FilterConfig{IncludeTables: []string{"T_*", "regex:^ORD"}, ExcludeTables: []string{"*_BAK"}}
+	if err := f.compile(); err != nil {
+		t.Fatalf("compile() error: %v", err)
+	}
+	cases := map[string]bool{
+		"T_ORDER":     true,
+		"t_order":     true,
+		"ORDERS":      true,
+		"T_ORDER_BAK": false,
+		"BILL":        false,
+	}
+	for table, want := range cases {
+		if got := f.MatchTable(table); got != want {
+			t.Errorf("MatchTable(%q) = %v, want %v", table, got, want)
+		}
+	}
+}
+
+func TestMappingConfigOracleTableName(t *testing.T) {
+	cases := []struct {
+		name    string
+		mapping MappingConfig
+		table   string
+		want    string
+	}{
+		{name: "default upper", table: "t_order", want: "T_ORDER"},
+		{name: "lower case", mapping: MappingConfig{TableCase: NameCaseLower}, table: "T_Order", want: "t_order"},
+		{name: "preserve case", mapping: MappingConfig{TableCase: NameCasePreserve}, table: "T_Order", want: "T_Order"},
+		{name: "case mode ignores case", mapping: MappingConfig{TableCase: "LOWER"}, table: "T_ORDER", want: "t_order"},
+		{name: "explicit pair", mapping: MappingConfig{Tables: map[string]string{"ORDERS_NEW": "Orders"}}, table: "orders_new", want: "ORDERS"},
+		{name: "explicit pair preserve", mapping: MappingConfig{Tables: map[string]string{"orders_new": "Orders"}, TableCase: NameCasePreserve}, table: "ORDERS_NEW", want: "Orders"},
+		{name: "explicit pair skips rewrite", mapping: MappingConfig{Tables: map[string]string{"T_ORDER": "ORDERS"}, TablePrefix: []RewriteRule{{MySQL: "T_", Oracle: "TB_"}}}, table: "T_ORDER", want: "ORDERS"},
+		{name: "prefix rewrite", mapping: MappingConfig{TablePrefix: []RewriteRule{{MySQL: "t_", Oracle: "TB_"}}}, table: "T_ORDER", want: "TB_ORDER"},
+		{name: "suffix rewrite", mapping: MappingConfig{TableSuffix: []RewriteRule{{MySQL: "_NEW", Oracle: ""}}}, table: "ORDER_new", want: "ORDER"},
+		{name: "prefix and suffix rewrite", mapping: MappingConfig{TablePrefix: []RewriteRule{{MySQL: "T_", Oracle: ""}}, TableSuffix: []RewriteRule{{MySQL: "_V2", Oracle: "_HIS"}}}, table: "T_ORDER_V2", want: "ORDER_HIS"},
+		{name: "first prefix rule wins", mapping: MappingConfig{TablePrefix: []RewriteRule{{MySQL: "T_", Oracle: "A_"}, {MySQL: "T_O", Oracle: "B_"}}}, table: "T_ORDER", want: "A_ORDER"},
+		{name: "prefix longer than name", mapping: MappingConfig{TablePrefix: []RewriteRule{{MySQL: "T_ORDER_", Oracle: ""}}}, table: "T_ORDER", want: "T_ORDER"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.mapping.OracleTableName(c.table); got != c.want {
+				t.Errorf("OracleTableName(%q) = %q, want %q", c.table, got, c.want)
+			}
+		})
+	}
+}
+
+func TestMappingConfigOracleColumnName(t *testing.T) {
+	cases := []struct {
+		name    string
+		mapping MappingConfig
+		table   string
+		column  string
+		want    string
+	}{
+		{name: "default upper", table: "T_ORDER", column: "amount", want: "AMOUNT"},
+		{name: "lower case", mapping: MappingConfig{ColumnCase: NameCaseLower}, table: "T_ORDER", column: "AMOUNT", want: "amount"},
+		{name: "preserve case", mapping: MappingConfig{ColumnCase: NameCasePreserve}, table: "T_ORDER", column: "Amount", want: "Amount"},
+		{name: "table case independent", mapping: MappingConfig{TableCase: NameCaseLower}, table: "T_ORDER", column: "amount", want: "AMOUNT"},
+		{name: "column pair", mapping: MappingConfig{Columns: map[string]string{"AMT": "AMOUNT"}}, table: "T_ORDER", column: "amt", want: "AMOUNT"},
+		{name: "table column pair", mapping: MappingConfig{Columns: map[string]string{"t_order.amt": "ORDER_AMOUNT"}}, table: "T_ORDER", column: "AMT", want: "ORDER_AMOUNT"},
+		{name: "table column pair wins", mapping: MappingConfig{Columns: map[string]string{"AMT": "AMOUNT", "T_ORDER.AMT": "ORDER_AMOUNT"}}, table: "T_ORDER", column: "AMT", want: "ORDER_AMOUNT"},
+		{name: "table column pair other table", mapping: MappingConfig{Columns: map[string]string{"AMT": "AMOUNT", "T_ORDER.AMT": "ORDER_AMOUNT"}}, table: "T_BILL", column: "AMT", want: "AMOUNT"},
+		{name: "column pair preserve", mapping: MappingConfig{Columns: map[string]string{"AMT": "Amount"}, ColumnCase: NameCasePreserve}, table: "T_ORDER", column: "AMT", want: "Amount"},
+		{name: "prefix rewrite", mapping: MappingConfig{ColumnPrefix: []RewriteRule{{MySQL: "C_", Oracle: "COL_"}}}, table: "T_ORDER", column: "c_amount", want: "COL_AMOUNT"},
+		{name: "suffix rewrite", mapping: MappingConfig{ColumnSuffix: []RewriteRule{{MySQL: "_NEW", Oracle: ""}}}, table: "T_ORDER", column: "AMOUNT_NEW", want: "AMOUNT"},
+		{name: "column pair skips rewrite", mapping: MappingConfig{Columns: map[string]string{"C_AMT": "AMT"}, ColumnPrefix: []RewriteRule{{MySQL: "C_", Oracle: "COL_"}}}, table: "T_ORDER", column: "C_AMT", want: "AMT"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.mapping.OracleColumnName(c.table, c.column); got != c.want {
+				t.Errorf("OracleColumnName(%q, %q) = %q, want %q", c.table, c.column, got, c.want)
+			}
+		})
+	}
+}
+
+// validConfig 返回通过校验的最小配置
+func validConfig() *Config {
+	return &Config{
+		AppConfig: AppConfig{
+			BatchSize:   100,
+			InitThread:  10,
+			TableThread: 4,
+			SQLThread:   8,
+			ChunkSize:   100000,
+			CallTimeout: 36000,
+		},
+		OracleConfig: OracleConfig{Username: "scan", Host: "127.0.0.1", Port: 1521, ServiceName: "orcl", Charset: "al32utf8", Schema: "marvin"},
+		MySQLConfig:  MySQLConfig{Username: "scan", Host: "127.0.0.1", Port: 4000, Charset: "utf8mb4", Schema: "marvin"},
+		MetaConfig:   MetaConfig{Username: "scan", Host: "127.0.0.1", Port: 3306, MetaSchema: "scan"},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validConfig().Validate(); err != nil {
+		t.Fatalf("Validate() on valid config error: %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		modify func(c *Config)
+		want   []string
+	}{
+		{
+			name: "multiple problems reported together",
+			modify: func(c *Config) {
+				c.AppConfig.BatchSize = 0
+				c.AppConfig.MaxSampleRows = -1
+				c.AppConfig.ScanMode = "fast"
+				c.OracleConfig.Host = ""
+				c.MySQLConfig.Charset = "latin1"
+				c.MetaConfig.MetaSchema = " "
+				c.MappingConfig.ColumnCase = "camel"
+			},
+			want: []string{
+				"config [app] batch-size [0] must be greater than 0",
+				"config [app] max-sample-rows [-1] can't be less than 0",
+				"config [app] scan-mode [fast] isn't support",
+				"config [oracle] host is required",
+				"config [mysql] charset [latin1] isn't support",
+				"config [meta] meta-schema is required",
+				"config [mapping] column-case [camel] isn't support",
+			},
+		},
+		{
+			name: "schema pairs",
+			modify: func(c *Config) {
+				c.OracleConfig.Schema, c.MySQLConfig.Schema = "", ""
+				c.SchemaConfig = []SchemaPair{
+					{OracleSchema: "ora1", MySQLSchema: "my1"},
+					{OracleSchema: "ORA1", MySQLSchema: "MY1"},
+					{OracleSchema: "ora2"},
+				}
+			},
+			want: []string{
+				"config [schema] oracle-schema [ORA1] mysql-schema [MY1] is duplicate",
+				"config [schema] #3 mysql-schema is required",
+			},
+		},
+		{
+			name: "filter and check types",
+			modify: func(c *Config) {
+				c.AppConfig.CheckTypes = []string{"integer", "bitmap"}
+				c.FilterConfig.IncludeTables = []string{"regex:("}
+			},
+			want: []string{
+				"config [app] check-types [bitmap] isn't support",
+				"filter pattern [regex:(] regex compile failed",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := validConfig()
+			c.modify(cfg)
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatalf("Validate() error = nil, want %d errors", len(c.want))
+			}
+			joined, ok := err.(interface{ Unwrap() []error })
+			if !ok || len(joined.Unwrap()) != len(c.want) {
+				t.Errorf("Validate() error = %v, want %d joined errors", err, len(c.want))
+			}
+			for _, w := range c.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("Validate() error = %v, want contains %q", err, w)
+				}
+			}
+		})
+	}
+}
+
+func TestSecretRedact(t *testing.T) {
+	const password = "p@ss'w0rd"
+	cfg := validConfig()
+	cfg.OracleConfig.Password = password
+	cfg.MySQLConfig.Password = password
+	cfg.MetaConfig.Password = password
+
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	outputs := map[string]string{
+		"String()":        cfg.OracleConfig.Password.String(),
+		"%s":              fmt.Sprintf("%s", cfg.OracleConfig.Password),
+		"%v":              fmt.Sprintf("%v", cfg.OracleConfig.Password),
+		"%v struct":       fmt.Sprintf("%v", cfg.OracleConfig),
+		"%+v struct":      fmt.Sprintf("%+v", cfg.MySQLConfig),
+		"json":            string(b),
+		"config String()": cfg.String(),
+	}
+	for name, out := range outputs {
+		if strings.Contains(out, password) {
+			t.Errorf("%s output %q contains plain password", name, out)
+		}
+		if !strings.Contains(out, redacted) {
+			t.Errorf("%s output %q doesn't contain %q", name, out, redacted)
+		}
+	}
+
+	if got := cfg.OracleConfig.Password.Reveal(); got != password {
+		t.Errorf("Reveal() = %q, want %q", got, password)
+	}
+	if got := Secret("").String(); got != "" {
+		t.Errorf("empty Secret String() = %q, want empty", got)
+	}
+}
+
+const testConfigTOML = `
+[app]
+init-thread = 10
+table-thread = 4
+sql-thread = 8
+batch-size = 100
+chunk-size = 100000
+call-timeout = 36000
+
+[oracle]
+username = "scan"
+password = "toml-oracle"
+%s
+host = "127.0.0.1"
+port = 1521
+service-name = "orcl"
+charset = "al32utf8"
+schema = "marvin"
+
+[mysql]
+username = "scan"
+password = "toml-mysql"
+host = "127.0.0.1"
+port = 4000
+charset = "utf8mb4"
+schema = "marvin"
+
+[meta]
+username = "scan"
+password = "toml-meta"
+host = "127.0.0.1"
+port = 3306
+meta-schema = "scan"
+`
+
+func TestParsePasswordPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "oracle.password")
+	if err := os.WriteFile(passwordFile, []byte("file-oracle\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name     string
+		oracle   string
+		envValue string
+		args     []string
+		want     string
+	}{
+		{name: "toml", want: "toml-oracle"},
+		{name: "env over toml", oracle: `password-env = "SCAN_TEST_ORACLE_PASSWORD"`, envValue: "env-oracle", want: "env-oracle"},
+		{name: "file over toml", oracle: fmt.Sprintf("password-file = %q", passwordFile), want: "file-oracle"},
+		{name: "file over env", oracle: fmt.Sprintf("password-env = \"SCAN_TEST_ORACLE_PASSWORD\"\npassword-file = %q", passwordFile), envValue: "env-oracle", want: "file-oracle"},
+		{name: "flag over toml", args: []string{"--oracle-password", "flag-oracle"}, want: "flag-oracle"},
+		{name: "flag over env", oracle: `password-env = "SCAN_TEST_ORACLE_PASSWORD"`, envValue: "env-oracle", args: []string{"--oracle-password", "flag-oracle"}, want: "flag-oracle"},
+		{name: "flag over file", oracle: fmt.Sprintf("password-file = %q", passwordFile), args: []string{"--oracle-password", "flag-oracle"}, want: "flag-oracle"},
+		{name: "flag after subcommand", oracle: fmt.Sprintf("password-file = %q", passwordFile), args: []string{"scan", "--oracle-password", "flag-oracle"}, want: "flag-oracle"},
+	}
+	for i, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.envValue != "" {
+				t.Setenv("SCAN_TEST_ORACLE_PASSWORD", c.envValue)
+			}
+			file := filepath.Join(dir, fmt.Sprintf("config%d.toml", i))
+			if err := os.WriteFile(file, []byte(fmt.Sprintf(testConfigTOML, c.oracle)), 0600); err != nil {
+				t.Fatal(err)
+			}
+			cfg := NewConfig()
+			if err := cfg.Parse(append([]string{"--config", file}, c.args...)); err != nil {
+				t.Fatalf("Parse() error: %v", err)
+			}
+			if got := cfg.OracleConfig.Password.Reveal(); got != c.want {
+				t.Errorf("oracle password = %q, want %q", got, c.want)
+			}
+			// 未指定的数据库密码不受其他数据库命令行参数影响
+			if got := cfg.MySQLConfig.Password.Reveal(); got != "toml-mysql" {
+				t.Errorf("mysql password = %q, want %q", got, "toml-mysql")
+			}
+		})
+	}
+}
+
+func TestParsePasswordEnvUnset(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(file, []byte(fmt.Sprintf(testConfigTOML, `password-env = "SCAN_TEST_UNSET_PASSWORD"`)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Unsetenv("SCAN_TEST_UNSET_PASSWORD")
+	err := NewConfig().Parse([]string{"--config", file})
+	if err == nil || !strings.Contains(err.Error(), "password-env [SCAN_TEST_UNSET_PASSWORD] isn't set") {
+		t.Errorf("Parse() error = %v, want password-env isn't set", err)
+	}
+}
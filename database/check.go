@@ -0,0 +1,465 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package database
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/greatcloak/decimal"
+	"github.com/wentaojin/scan/common"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// CheckTypeInteger decimal(p>=19,0) 字段是否可 modify 为整型
+	CheckTypeInteger = "INTEGER"
+	// CheckTypeDecimal decimal(p,s) 字段整数位以及小数位是否容纳源端数据
+	CheckTypeDecimal = "DECIMAL"
+	// CheckTypeScale decimal(p,s>0) 字段实际小数位，是否可 modify 为整型或者缩小小数位
+	CheckTypeScale = "SCALE"
+	// CheckTypeFloat double/float 字段源端数值经 float64/float32 转换是否丢失精度
+	CheckTypeFloat = "FLOAT"
+	// CheckTypeString varchar(n)/char(n) 字段源端数据转换目标端字符集后字符长度是否超出 n
+	CheckTypeString = "STRING"
+	// CheckTypeTemporal datetime/timestamp/date 字段源端数据是否超出目标端时间类型取值范围
+	CheckTypeTemporal = "TEMPORAL"
+)
+
+// CheckColumn 待扫描字段以及字段校验类型，字段定义取自目标端 mysql，ColumnNameS 为映射后的源端 oracle 字段名
+type CheckColumn struct {
+	ColumnName        string
+	ColumnNameS       string
+	DataType          string
+	Precision         int
+	Scale             int
+	Length            int
+	Charset           string
+	DatetimePrecision int
+	// FixedScale float(M,D)、double(M,D) 声明小数位 D 记录于 Scale
+	FixedScale bool
+	// DataDefault 字段默认值，无默认值为空字符串
+	DataDefault string
+	// TemporalMin、TemporalMax 时间类型取值范围，timestamp 为目标端 time_zone 本地时间
+	TemporalMin string
+	TemporalMax string
+	Checks      []string
+}
+
+func NewCheckColumns(columns []map[string]string, checkTypes []string) ([]CheckColumn, error) {
+	enabled := make(map[string]struct{})
+	for _, c := range checkTypes {
+		enabled[strings.ToUpper(c)] = struct{}{}
+	}
+	if len(enabled) == 0 {
+		enabled[CheckTypeInteger] = struct{}{}
+	}
+
+	var results []CheckColumn
+	for _, c := range columns {
+		precision, err := strconv.Atoi(c["DATA_PRECISION"])
+		if err != nil {
+			return results, err
+		}
+		scale, err := strconv.Atoi(c["DATA_SCALE"])
+		if err != nil {
+			return results, err
+		}
+		length, err := strconv.Atoi(c["DATA_LENGTH"])
+		if err != nil {
+			return results, err
+		}
+		datetimePrecision, err := strconv.Atoi(c["DATETIME_PRECISION"])
+		if err != nil {
+			return results, err
+		}
+		column := CheckColumn{
+			ColumnName:        c["COLUMN_NAME"],
+			ColumnNameS:       strings.ToUpper(c["COLUMN_NAME"]),
+			DataType:          strings.ToUpper(c["DATA_TYPE"]),
+			Precision:         precision,
+			Scale:             scale,
+			Length:            length,
+			Charset:           strings.ToUpper(c["CHARACTER_SET_NAME"]),
+			DatetimePrecision: datetimePrecision,
+		}
+		if !strings.EqualFold(c["DATA_DEFAULT"], "NULLSTRING") {
+			column.DataDefault = c["DATA_DEFAULT"]
+		}
+
+		switch column.DataType {
+		case "DECIMAL":
+			// bigint <= decimal(19,0)
+			if _, ok := enabled[CheckTypeInteger]; ok && scale == 0 && precision >= 19 {
+				column.Checks = append(column.Checks, CheckTypeInteger)
+			}
+			if _, ok := enabled[CheckTypeDecimal]; ok {
+				column.Checks = append(column.Checks, CheckTypeDecimal)
+			}
+			if _, ok := enabled[CheckTypeScale]; ok && scale > 0 {
+				column.Checks = append(column.Checks, CheckTypeScale)
+			}
+		case "DOUBLE", "FLOAT":
+			if _, ok := enabled[CheckTypeFloat]; ok {
+				column.Checks = append(column.Checks, CheckTypeFloat)
+			}
+			column.FixedScale = strings.Contains(c["COLUMN_TYPE"], ",")
+		case "VARCHAR", "CHAR":
+			if _, ok := enabled[CheckTypeString]; ok {
+				column.Checks = append(column.Checks, CheckTypeString)
+			}
+		case "DATETIME", "TIMESTAMP", "DATE":
+			if _, ok := enabled[CheckTypeTemporal]; ok {
+				column.Checks = append(column.Checks, CheckTypeTemporal)
+			}
+			column.TemporalMin, column.TemporalMax = common.MySQLDatetimeMin, common.MySQLDatetimeMax
+			if column.DataType == "TIMESTAMP" {
+				column.TemporalMin, column.TemporalMax = common.MySQLTimestampRange(0)
+			}
+		}
+
+		if len(column.Checks) > 0 {
+			results = append(results, column)
+		}
+	}
+	return results, nil
+}
+
+func (c CheckColumn) HasCheck(checkType string) bool {
+	for _, t := range c.Checks {
+		if strings.EqualFold(t, checkType) {
+			return true
+		}
+	}
+	return false
+}
+
+// OnlyCheck 字段是否仅有指定校验类型
+func (c CheckColumn) OnlyCheck(checkType string) bool {
+	return len(c.Checks) == 1 && c.HasCheck(checkType)
+}
+
+// checkStat 单个 chunk 字段校验统计
+type checkStat struct {
+	sourceDBCharset  string
+	targetDBCharset  string
+	minValue         decimal.Decimal
+	maxValue         decimal.Decimal
+	nullCount        int64
+	notNullCount     int64
+	violateCount     int64
+	maxIntegerDigits int
+	maxScale         int
+	maxLength        int
+	maxByteLength    int
+	minTemporal      string
+	maxTemporal      string
+}
+
+func newCheckStat(sourceDBCharset, targetDBCharset string) *checkStat {
+	return &checkStat{
+		sourceDBCharset: sourceDBCharset,
+		targetDBCharset: targetDBCharset,
+	}
+}
+
+// check 校验字段值，返回越界数据字段信息，未越界返回 nil
+func (s *checkStat) check(c CheckColumn, checkType string, raw []byte) (*Column, error) {
+	// NULL 值不影响字段类型判定，仅记录 NULL 行数
+	if raw == nil || string(raw) == "" {
+		s.nullCount++
+		return nil, nil
+	}
+	s.notNullCount++
+
+	var (
+		column *Column
+		err    error
+	)
+	switch checkType {
+	case CheckTypeString:
+		column, err = s.checkString(c, raw)
+	case CheckTypeTemporal:
+		column = s.checkTemporal(c, raw)
+	default:
+		column, err = s.checkNumber(c, checkType, raw)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if column != nil {
+		s.violateCount++
+	}
+	return column, nil
+}
+
+func (s *checkStat) checkNumber(c CheckColumn, checkType string, raw []byte) (*Column, error) {
+	value, err := decimal.NewFromString(string(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	if s.notNullCount == 1 || value.Cmp(s.minValue) == -1 {
+		s.minValue = value
+	}
+	if s.notNullCount == 1 || value.Cmp(s.maxValue) == 1 {
+		s.maxValue = value
+	}
+	integerDigits, scale := common.DecimalDigits(value)
+	if integerDigits > s.maxIntegerDigits {
+		s.maxIntegerDigits = integerDigits
+	}
+	if scale > s.maxScale {
+		s.maxScale = scale
+	}
+
+	switch checkType {
+	case CheckTypeInteger:
+		return checkIntegerValue(c, value, string(raw)), nil
+	case CheckTypeDecimal:
+		return checkDecimalValue(c, integerDigits, scale, string(raw)), nil
+	case CheckTypeScale:
+		// 仅统计字段实际小数位，不存在越界数据
+		return nil, nil
+	case CheckTypeFloat:
+		return checkFloatValue(c, value, scale, string(raw)), nil
+	default:
+		return nil, fmt.Errorf("column [%s] check type [%s] isn't support", c.ColumnName, checkType)
+	}
+}
+
+func (s *checkStat) checkString(c CheckColumn, raw []byte) (*Column, error) {
+	utf8Raw, err := common.CharsetConvert(raw, s.sourceDBCharset, common.CharsetUTF8MB4)
+	if err != nil {
+		return nil, fmt.Errorf("column [%s] charset convert failed, %v", c.ColumnName, err)
+	}
+	// 字节长度按字段字符集计算，字段字符集不支持转换时按连接字符集计算
+	columnCharset, ok := common.MigrateMYSQLCompatibleCharsetStringConvertMapping[c.Charset]
+	if !ok {
+		columnCharset = s.targetDBCharset
+	}
+	targetRaw, err := common.CharsetConvert(utf8Raw, common.CharsetUTF8MB4, columnCharset)
+	if err != nil {
+		return nil, fmt.Errorf("column [%s] charset convert failed, %v", c.ColumnName, err)
+	}
+
+	// mysql 写入 char/varchar 超出字段长度的末尾空格直接截断，不计入字符长度
+	length := utf8.RuneCount(bytes.TrimRight(utf8Raw, " "))
+	if length > s.maxLength {
+		s.maxLength = length
+	}
+	if len(targetRaw) > s.maxByteLength {
+		s.maxByteLength = len(targetRaw)
+	}
+
+	if length > c.Length {
+		return &Column{
+			ColumnName:           strings.ToUpper(c.ColumnName),
+			ColumnValue:          truncateValue(string(utf8Raw)),
+			ColumnBoundary:       "LENGTH",
+			ColumnBigint:         "UNKNOWN",
+			ColumnUnsingedBigint: "UNKNOWN",
+		}, nil
+	}
+	return nil, nil
+}
+
+func (s *checkStat) checkTemporal(c CheckColumn, raw []byte) *Column {
+	// SYYYY 正数年份符号位为空格
+	value := strings.TrimSpace(string(raw))
+	if s.notNullCount == 1 || common.CompareTemporal(value, s.minTemporal) == -1 {
+		s.minTemporal = value
+	}
+	if s.notNullCount == 1 || common.CompareTemporal(value, s.maxTemporal) == 1 {
+		s.maxTemporal = value
+	}
+	return checkTemporalValue(c, value)
+}
+
+func (s *checkStat) summary(m Full, c CheckColumn, checkType string) Summary {
+	summary := Summary{
+		RunID:            m.RunID,
+		SchemaNameS:      m.SchemaNameS,
+		SchemaNameT:      m.SchemaNameT,
+		TableNameT:       m.TableNameT,
+		ChunkID:          m.ID,
+		ChunkDetailT:     m.ChunkDetailT,
+		ColumnName:       strings.ToUpper(c.ColumnName),
+		CheckType:        checkType,
+		NullCount:        s.nullCount,
+		NotNullCount:     s.notNullCount,
+		ViolateCount:     s.violateCount,
+		MaxIntegerDigits: s.maxIntegerDigits,
+		MaxScale:         s.maxScale,
+		MaxLength:        s.maxLength,
+		MaxByteLength:    s.maxByteLength,
+	}
+	if s.notNullCount == 0 {
+		return summary
+	}
+	switch checkType {
+	case CheckTypeString:
+		// 字符类型字段无取值范围
+	case CheckTypeTemporal:
+		summary.MinValue = s.minTemporal
+		summary.MaxValue = s.maxTemporal
+	default:
+		summary.MinValue = s.minValue.String()
+		summary.MaxValue = s.maxValue.String()
+	}
+	return summary
+}
+
+// checkIntegerValue 逐行按 BIGINT 取值范围记录越界数据，ColumnUnsingedBigint 标记是否处于无符号 BIGINT 取值范围
+// 单行无法确定全表是否建议无符号整型，汇总时全表数据处于无符号取值范围则不计越界
+func checkIntegerValue(c CheckColumn, value decimal.Decimal, raw string) *Column {
+	switch {
+	// > BIGINT
+	case value.Cmp(common.BigintMax) == 1:
+		// > UNSIGNED BIGINT
+		if value.Cmp(common.UnsignedBigintMax) == 1 {
+			return &Column{
+				ColumnName:           strings.ToUpper(c.ColumnName),
+				ColumnValue:          raw,
+				ColumnBoundary:       "MAX",
+				ColumnBigint:         fmt.Sprintf("> %s", common.BigintMax),
+				ColumnUnsingedBigint: fmt.Sprintf("> %s", common.UnsignedBigintMax),
+			}
+		}
+		// <= UNSIGNED BIGINT
+		return &Column{
+			ColumnName:           strings.ToUpper(c.ColumnName),
+			ColumnValue:          raw,
+			ColumnBoundary:       "MAX",
+			ColumnBigint:         fmt.Sprintf("> %s", common.BigintMax),
+			ColumnUnsingedBigint: fmt.Sprintf("<= %s", common.UnsignedBigintMax),
+		}
+	// < BIGINT, negative values never fit UNSIGNED BIGINT
+	case value.Cmp(common.BigintMin) == -1:
+		return &Column{
+			ColumnName:           strings.ToUpper(c.ColumnName),
+			ColumnValue:          raw,
+			ColumnBoundary:       "MIN",
+			ColumnBigint:         fmt.Sprintf("< %s", common.BigintMin),
+			ColumnUnsingedBigint: "< 0",
+		}
+	}
+	return nil
+}
+
+func checkDecimalValue(c CheckColumn, integerDigits, scale int, raw string) *Column {
+	switch {
+	// 整数位超出 decimal(p,s) p-s 位，数据写入报错
+	case integerDigits > c.Precision-c.Scale:
+		return &Column{
+			ColumnName:           strings.ToUpper(c.ColumnName),
+			ColumnValue:          raw,
+			ColumnBoundary:       "PRECISION",
+			ColumnBigint:         "UNKNOWN",
+			ColumnUnsingedBigint: "UNKNOWN",
+		}
+	// 小数位超出 decimal(p,s) s 位，数据写入截断
+	case scale > c.Scale:
+		return &Column{
+			ColumnName:           strings.ToUpper(c.ColumnName),
+			ColumnValue:          raw,
+			ColumnBoundary:       "SCALE",
+			ColumnBigint:         "UNKNOWN",
+			ColumnUnsingedBigint: "UNKNOWN",
+		}
+	}
+	return nil
+}
+
+// checkFloatValue 校验数值经 float32/float64 转换是否丢失精度，声明小数位时小数位超出 D 位写入四舍五入同样记录
+func checkFloatValue(c CheckColumn, value decimal.Decimal, scale int, raw string) *Column {
+	if c.FixedScale && scale > c.Scale {
+		return &Column{
+			ColumnName:           strings.ToUpper(c.ColumnName),
+			ColumnValue:          raw,
+			ColumnBoundary:       "SCALE",
+			ColumnBigint:         "UNKNOWN",
+			ColumnUnsingedBigint: "UNKNOWN",
+		}
+	}
+
+	var roundTrip decimal.Decimal
+	switch c.DataType {
+	case "FLOAT":
+		f, err := strconv.ParseFloat(raw, 32)
+		// 超出 float32 取值范围
+		if err != nil {
+			roundTrip = decimal.Zero
+			break
+		}
+		roundTrip = decimal.NewFromFloat32(float32(f))
+	default:
+		f, err := strconv.ParseFloat(raw, 64)
+		// 超出 float64 取值范围
+		if err != nil {
+			roundTrip = decimal.Zero
+			break
+		}
+		roundTrip = decimal.NewFromFloat(f)
+	}
+
+	if roundTrip.Equal(value) {
+		return nil
+	}
+	return &Column{
+		ColumnName:           strings.ToUpper(c.ColumnName),
+		ColumnValue:          raw,
+		ColumnBoundary:       "PRECISION",
+		ColumnBigint:         "UNKNOWN",
+		ColumnUnsingedBigint: "UNKNOWN",
+	}
+}
+
+// checkTemporalValue 校验时间是否超出目标端时间类型取值范围，处于 datetime 取值范围建议 datetime，否则无可容纳的时间类型
+func checkTemporalValue(c CheckColumn, value string) *Column {
+	var boundary string
+	switch {
+	case common.CompareTemporal(value, c.TemporalMin) == -1:
+		boundary = "MIN"
+	case common.CompareTemporal(value, c.TemporalMax) == 1:
+		boundary = "MAX"
+	default:
+		return nil
+	}
+
+	suggest := "UNKNOWN"
+	if common.InTemporalRange(value, common.MySQLDatetimeMin, common.MySQLDatetimeMax) {
+		suggest = common.MySQLDatetimeType(c.DatetimePrecision)
+	}
+	return &Column{
+		ColumnName:           strings.ToUpper(c.ColumnName),
+		ColumnValue:          value,
+		ColumnBoundary:       boundary,
+		ColumnBigint:         "UNKNOWN",
+		ColumnUnsingedBigint: "UNKNOWN",
+		ColumnSuggest:        suggest,
+	}
+}
+
+// truncateValue 截断越界数据字段值，避免超出 scan 表 column_value 字段长度
+func truncateValue(value string) string {
+	runes := []rune(value)
+	if len(runes) > 255 {
+		return string(runes[:255])
+	}
+	return value
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package database
+
+import (
+	"testing"
+
+	"github.com/greatcloak/decimal"
+	"github.com/wentaojin/scan/common"
+)
+
+func TestCheckFloatValue(t *testing.T) {
+	cases := []struct {
+		name         string
+		column       CheckColumn
+		raw          string
+		wantBoundary string
+	}{
+		{name: "double exact", column: CheckColumn{DataType: "DOUBLE"}, raw: "1.25"},
+		{name: "double precision lost", column: CheckColumn{DataType: "DOUBLE"}, raw: "0.12345678901234567890", wantBoundary: "PRECISION"},
+		{name: "float shortest round trip", column: CheckColumn{DataType: "FLOAT"}, raw: "0.1"},
+		{name: "float precision lost", column: CheckColumn{DataType: "FLOAT"}, raw: "16777217", wantBoundary: "PRECISION"},
+		{name: "float exact", column: CheckColumn{DataType: "FLOAT"}, raw: "0.5"},
+		{name: "double unscaled many digits", column: CheckColumn{DataType: "DOUBLE"}, raw: "1.125"},
+		{name: "double(10,2) within scale", column: CheckColumn{DataType: "DOUBLE", Scale: 2, FixedScale: true}, raw: "1.25"},
+		{name: "double(10,2) trailing zero", column: CheckColumn{DataType: "DOUBLE", Scale: 2, FixedScale: true}, raw: "1.250"},
+		{name: "double(10,2) over scale", column: CheckColumn{DataType: "DOUBLE", Scale: 2, FixedScale: true}, raw: "1.125", wantBoundary: "SCALE"},
+		{name: "float(7,0) over scale", column: CheckColumn{DataType: "FLOAT", Scale: 0, FixedScale: true}, raw: "2.5", wantBoundary: "SCALE"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			value := decimal.RequireFromString(c.raw)
+			_, scale := common.DecimalDigits(value)
+			var got string
+			if column := checkFloatValue(c.column, value, scale, c.raw); column != nil {
+				got = column.ColumnBoundary
+			}
+			if got != c.wantBoundary {
+				t.Errorf("checkFloatValue(%q) boundary = %q, want %q", c.raw, got, c.wantBoundary)
+			}
+		})
+	}
+}
@@ -24,11 +24,14 @@ import (
 
 type Full struct {
 	ID            uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
-	SchemaNameT   string `gorm:"type:varchar(100);not null;index:idx_dbtype_st_map,unique;comment:'目标端 schema'" json:"schema_name_t"`
-	TableNameT    string `gorm:"type:varchar(100);not null;index:idx_dbtype_st_map,unique;comment:'目标端表名'" json:"table_name_t"`
+	RunID         uint   `gorm:"not null;index:idx_run_schema_chunk,unique;comment:'运行编号'" json:"run_id"`
+	SchemaNameS   string `gorm:"type:varchar(100);not null;index:idx_run_schema_chunk,unique;comment:'源端 schema'" json:"schema_name_s"`
+	SchemaNameT   string `gorm:"type:varchar(100);not null;index:idx_run_schema_chunk,unique;comment:'目标端 schema'" json:"schema_name_t"`
+	TableNameT    string `gorm:"type:varchar(100);not null;index:idx_run_schema_chunk,unique;comment:'目标端表名'" json:"table_name_t"`
+	TableNameS    string `gorm:"type:varchar(100);comment:'源端表名'" json:"table_name_s"`
 	SQLHint       string `gorm:"type:varchar(300);comment:'sql hint'" json:"sql_hint"`
 	ColumnDetailT string `gorm:"type:text;comment:'源端查询字段信息'" json:"column_detail_t"`
-	ChunkDetailT  string `gorm:"type:varchar(300);not null;index:idx_dbtype_st_map,unique;comment:'表 chunk 切分信息'" json:"chunk_detail_t"`
+	ChunkDetailT  string `gorm:"type:varchar(300);not null;index:idx_run_schema_chunk,unique;comment:'表 chunk 切分信息'" json:"chunk_detail_t"`
 	TaskStatus    string `gorm:"type:varchar(30);not null;comment:'任务 chunk 状态'" json:"task_status"`
 	*Meta         `gorm:"-" json:"-"`
 }
@@ -77,7 +80,9 @@ func (rw *Full) UpdateFullSyncMetaChunk(ctx context.Context, detailS *Full, upda
 		return err
 	}
 	if err = rw.DB(ctx).Model(Full{}).
-		Where("schema_name_t = ? AND table_name_t = ? AND chunk_detail_t = ?",
+		Where("run_id = ? AND schema_name_s = ? AND schema_name_t = ? AND table_name_t = ? AND chunk_detail_t = ?",
+			detailS.RunID,
+			strings.ToUpper(detailS.SchemaNameS),
 			strings.ToUpper(detailS.SchemaNameT),
 			strings.ToUpper(detailS.TableNameT),
 			detailS.ChunkDetailT).
@@ -86,3 +91,46 @@ func (rw *Full) UpdateFullSyncMetaChunk(ctx context.Context, detailS *Full, upda
 	}
 	return nil
 }
+
+func (rw *Full) UpdateFullSyncMetaTable(ctx context.Context, detailS *Full, updates map[string]interface{}) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err = rw.DB(ctx).Model(Full{}).
+		Where("run_id = ? AND schema_name_s = ? AND schema_name_t = ? AND table_name_t = ?",
+			detailS.RunID,
+			strings.ToUpper(detailS.SchemaNameS),
+			strings.ToUpper(detailS.SchemaNameT),
+			strings.ToUpper(detailS.TableNameT)).
+		Updates(updates).Error; err != nil {
+		return fmt.Errorf("update table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
+
+// FullStatus 表各状态 chunk 数
+type FullStatus struct {
+	SchemaNameS string `json:"schema_name_s"`
+	SchemaNameT string `json:"schema_name_t"`
+	TableNameT  string `json:"table_name_t"`
+	TaskStatus  string `json:"task_status"`
+	ChunkCount  int64  `json:"chunk_count"`
+}
+
+func (rw *Full) StatusFullSyncMeta(ctx context.Context, detailS *Full) ([]FullStatus, error) {
+	var status []FullStatus
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return status, err
+	}
+	if err = rw.DB(ctx).Model(Full{}).
+		Select("schema_name_s, schema_name_t, table_name_t, task_status, COUNT(1) AS chunk_count").
+		Where(detailS).
+		Group("schema_name_s, schema_name_t, table_name_t, task_status").
+		Order("schema_name_s, table_name_t").
+		Scan(&status).Error; err != nil {
+		return status, fmt.Errorf("status table [%s] record failed: %v", table, err)
+	}
+	return status, nil
+}
@@ -19,6 +19,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"github.com/wentaojin/scan/common"
 	"github.com/wentaojin/scan/config"
 	"github.com/wentaojin/scan/logger"
 	"go.uber.org/zap"
@@ -34,14 +35,14 @@ type Meta struct {
 func NewMetaDBEngine(ctx context.Context, mysqlCfg config.MetaConfig) (*Meta, error) {
 	// 创建元数据库
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/?charset=utf8mb4&parseTime=True&loc=Local",
-		mysqlCfg.Username, mysqlCfg.Password, mysqlCfg.Host, mysqlCfg.Port)
+		mysqlCfg.Username, mysqlCfg.Password.Reveal(), mysqlCfg.Host, mysqlCfg.Port)
 
 	mysqlDB, err := sql.Open("mysql", dsn)
 	if err != nil {
 		return &Meta{}, fmt.Errorf("error on open general database connection [%v]: %v", mysqlCfg.MetaSchema, err)
 	}
 
-	createSchema := fmt.Sprintf(`CREATE DATABASE IF NOT EXISTS %s`, mysqlCfg.MetaSchema)
+	createSchema := fmt.Sprintf(`CREATE DATABASE IF NOT EXISTS %s`, common.QuoteMySQLIdentifier(mysqlCfg.MetaSchema))
 	_, err = mysqlDB.ExecContext(ctx, createSchema)
 	if err != nil {
 		return &Meta{}, fmt.Errorf("error on exec meta database sql [%v]: %v", createSchema, err)
@@ -52,7 +53,7 @@ func NewMetaDBEngine(ctx context.Context, mysqlCfg config.MetaConfig) (*Meta, er
 	}
 
 	dsn = fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		mysqlCfg.Username, mysqlCfg.Password, mysqlCfg.Host, mysqlCfg.Port, mysqlCfg.MetaSchema)
+		mysqlCfg.Username, mysqlCfg.Password.Reveal(), mysqlCfg.Host, mysqlCfg.Port, mysqlCfg.MetaSchema)
 
 	l := logger.NewGormLogger(zap.L(), mysqlCfg.SlowThreshold)
 	l.SetAsDefault()
@@ -93,13 +94,43 @@ func (m *Meta) DB(ctx context.Context) *gorm.DB {
 	return m.GormDB.WithContext(ctx)
 }
 
+// Transaction 事务内执行 fn，fn 内通过 txnCtx 调用 DB 使用同一事务
+func (m *Meta) Transaction(ctx context.Context, fn func(txnCtx context.Context) error) error {
+	return m.DB(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, ctxTxnKey, tx))
+	})
+}
+
 func (m *Meta) MigrateTables() (err error) {
-	return m.migrateStream(
+	err = m.migrateStream(
+		new(Run),
 		new(Wait),
 		new(Full),
 		new(Scan),
+		new(Summary),
+		new(Violation),
 		new(Statistics),
+		new(Profile),
 	)
+	if err != nil {
+		return err
+	}
+	// wait、full 表唯一索引增加源端 schema 以及运行编号，删除历史版本唯一索引
+	legacyIndexes := []struct {
+		model interface{}
+		index string
+	}{
+		{model: &Wait{}, index: "idx_dbtype_st_map"},
+		{model: &Full{}, index: "idx_dbtype_st_map"},
+	}
+	for _, l := range legacyIndexes {
+		if m.GormDB.Migrator().HasIndex(l.model, l.index) {
+			if err = m.GormDB.Migrator().DropIndex(l.model, l.index); err != nil {
+				return fmt.Errorf("error on migrate stream: %v", err)
+			}
+		}
+	}
+	return nil
 }
 
 func (m *Meta) migrateStream(models ...interface{}) (err error) {
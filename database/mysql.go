@@ -25,7 +25,6 @@ import (
 )
 
 type MySQL struct {
-	Ctx     context.Context
 	MySQLDB *sql.DB
 }
 
@@ -34,7 +33,7 @@ func NewMySQLDBEngine(ctx context.Context, mysqlCfg config.MySQLConfig) (*MySQL,
 		mysqlCfg.ConnectParams = fmt.Sprintf("charset=%s&%s", strings.ToLower(mysqlCfg.Charset), mysqlCfg.ConnectParams)
 	}
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/?%s",
-		mysqlCfg.Username, mysqlCfg.Password, mysqlCfg.Host, mysqlCfg.Port, mysqlCfg.ConnectParams)
+		mysqlCfg.Username, mysqlCfg.Password.Reveal(), mysqlCfg.Host, mysqlCfg.Port, mysqlCfg.ConnectParams)
 
 	mysqlDB, err := sql.Open("mysql", dsn)
 	if err != nil {
@@ -46,12 +45,11 @@ func NewMySQLDBEngine(ctx context.Context, mysqlCfg config.MySQLConfig) (*MySQL,
 	mysqlDB.SetConnMaxLifetime(common.MySQLConnMaxLifeTime)
 	mysqlDB.SetConnMaxIdleTime(common.MySQLConnMaxIdleTime)
 
-	if err = mysqlDB.Ping(); err != nil {
+	if err = mysqlDB.PingContext(ctx); err != nil {
 		return nil, fmt.Errorf("error on ping mysql database connection: %v", err)
 	}
 
 	return &MySQL{
-		Ctx:     ctx,
 		MySQLDB: mysqlDB,
 	}, nil
 }
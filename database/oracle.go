@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"github.com/godror/godror"
 	"github.com/godror/godror/dsn"
-	"github.com/greatcloak/decimal"
 	"github.com/wentaojin/scan/common"
 	"github.com/wentaojin/scan/config"
 	"go.uber.org/zap"
@@ -31,7 +30,6 @@ import (
 )
 
 type Oracle struct {
-	Ctx      context.Context
 	OracleDB *sql.DB
 }
 
@@ -62,7 +60,7 @@ func NewOracleDBEngine(ctx context.Context, oraCfg config.OracleConfig) (*Oracle
 		return nil, err
 	}
 
-	oraDSN.Username, oraDSN.Password = oraCfg.Username, godror.NewPassword(oraCfg.Password)
+	oraDSN.Username, oraDSN.Password = oraCfg.Username, godror.NewPassword(oraCfg.Password.Reveal())
 
 	if !strings.EqualFold(oraCfg.PDBName, "") {
 		oraCfg.SessionParams = append(oraCfg.SessionParams, fmt.Sprintf(`ALTER SESSION SET CONTAINER = %s`, oraCfg.PDBName))
@@ -89,39 +87,38 @@ func NewOracleDBEngine(ctx context.Context, oraCfg config.OracleConfig) (*Oracle
 	sqlDB.SetMaxOpenConns(0)
 	sqlDB.SetConnMaxLifetime(0)
 
-	err = sqlDB.Ping()
+	err = sqlDB.PingContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error on ping oracle database connection:%v", err)
 	}
 	return &Oracle{
-		Ctx:      ctx,
 		OracleDB: sqlDB,
 	}, nil
 }
 
-func (o *Oracle) StartOracleChunkCreateTask(taskName string) error {
-	querySQL := common.StringsBuilder(`SELECT COUNT(1) COUNT FROM dba_parallel_execute_chunks WHERE TASK_NAME='`, taskName, `'`)
-	_, res, err := Query(o.Ctx, o.OracleDB, querySQL)
+func (o *Oracle) StartOracleChunkCreateTask(ctx context.Context, taskName string) error {
+	querySQL := common.StringsBuilder(`SELECT COUNT(1) COUNT FROM dba_parallel_execute_chunks WHERE TASK_NAME=`, common.QuoteOracleString(taskName))
+	_, res, err := Query(ctx, o.OracleDB, querySQL)
 	if err != nil {
 		return err
 	}
 	if res[0]["COUNT"] != "0" {
-		if err = o.CloseOracleChunkTask(taskName); err != nil {
+		if err = o.CloseOracleChunkTask(ctx, taskName); err != nil {
 			return err
 		}
 	}
 
 	createSQL := common.StringsBuilder(`BEGIN
-  DBMS_PARALLEL_EXECUTE.CREATE_TASK (task_name => '`, taskName, `');
+  DBMS_PARALLEL_EXECUTE.CREATE_TASK (task_name => `, common.QuoteOracleString(taskName), `);
 END;`)
-	_, err = o.OracleDB.ExecContext(o.Ctx, createSQL)
+	_, err = o.OracleDB.ExecContext(ctx, createSQL)
 	if err != nil {
 		return fmt.Errorf("oracle DBMS_PARALLEL_EXECUTE create task failed: %v, sql: %v", err, createSQL)
 	}
 	return nil
 }
 
-func (o *Oracle) StartOracleCreateChunkByRowID(taskName, schemaName, tableName string, chunkSize string, callTimeout int64) error {
+func (o *Oracle) StartOracleCreateChunkByRowID(ctx context.Context, taskName, schemaName, tableName string, chunkSize string, callTimeout int64) error {
 	deadline := time.Now().Add(time.Duration(callTimeout) * time.Second)
 
 	zap.L().Warn("split task schema table calltimeout",
@@ -131,13 +128,13 @@ func (o *Oracle) StartOracleCreateChunkByRowID(taskName, schemaName, tableName s
 		zap.Int64("calltimeout", callTimeout),
 		zap.String("deadline", deadline.String()))
 
-	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
 	defer cancel()
 
 	chunkSQL := common.StringsBuilder(`BEGIN
-  DBMS_PARALLEL_EXECUTE.CREATE_CHUNKS_BY_ROWID (task_name   => '`, taskName, `',
-                                               table_owner => '`, schemaName, `',
-                                               table_name  => '`, tableName, `',
+  DBMS_PARALLEL_EXECUTE.CREATE_CHUNKS_BY_ROWID (task_name   => `, common.QuoteOracleString(taskName), `,
+                                               table_owner => `, common.QuoteOracleString(schemaName), `,
+                                               table_name  => `, common.QuoteOracleString(tableName), `,
                                                by_row      => TRUE,
                                                chunk_size  => `, chunkSize, `);
 END;`)
@@ -149,12 +146,12 @@ END;`)
 	return nil
 }
 
-func (o *Oracle) GetOracleTableChunksByRowID(taskName string, callTimeout int64) ([]map[string]string, error) {
-	querySQL := common.StringsBuilder(`SELECT 'ROWID BETWEEN ''' || start_rowid || ''' AND ''' || end_rowid || '''' CMD FROM dba_parallel_execute_chunks WHERE  task_name = '`, taskName, `' ORDER BY chunk_id`)
+func (o *Oracle) GetOracleTableChunksByRowID(ctx context.Context, taskName string, callTimeout int64) ([]map[string]string, error) {
+	querySQL := common.StringsBuilder(`SELECT 'ROWID BETWEEN ''' || start_rowid || ''' AND ''' || end_rowid || '''' CMD FROM dba_parallel_execute_chunks WHERE  task_name = `, common.QuoteOracleString(taskName), ` ORDER BY chunk_id`)
 
 	deadline := time.Now().Add(time.Duration(callTimeout) * time.Second)
 
-	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
 	defer cancel()
 
 	_, res, err := Query(ctx, o.OracleDB, querySQL)
@@ -164,12 +161,12 @@ func (o *Oracle) GetOracleTableChunksByRowID(taskName string, callTimeout int64)
 	return res, nil
 }
 
-func (o *Oracle) CloseOracleChunkTask(taskName string) error {
+func (o *Oracle) CloseOracleChunkTask(ctx context.Context, taskName string) error {
 	clearSQL := common.StringsBuilder(`BEGIN
-  DBMS_PARALLEL_EXECUTE.DROP_TASK ('`, taskName, `');
+  DBMS_PARALLEL_EXECUTE.DROP_TASK (`, common.QuoteOracleString(taskName), `);
 END;`)
 
-	_, err := o.OracleDB.ExecContext(o.Ctx, clearSQL)
+	_, err := o.OracleDB.ExecContext(ctx, clearSQL)
 	if err != nil {
 		return fmt.Errorf("oracle DBMS_PARALLEL_EXECUTE drop task failed: %v, sql: %v", err, clearSQL)
 	}
@@ -177,82 +174,186 @@ END;`)
 	return nil
 }
 
-func (o *Oracle) GetOracleSchemaTable(schemaName string) ([]string, error) {
+func (o *Oracle) GetOracleSchemaTable(ctx context.Context, schemaName string) ([]string, error) {
 	var (
 		tables []string
 		err    error
 	)
-	_, res, err := Query(o.Ctx, o.OracleDB, fmt.Sprintf(`SELECT table_name AS TABLE_NAME FROM DBA_TABLES WHERE UPPER(owner) = UPPER('%s') AND (IOT_TYPE IS NUll OR IOT_TYPE='IOT')`, schemaName))
+	_, res, err := Query(ctx, o.OracleDB, fmt.Sprintf(`SELECT table_name AS TABLE_NAME FROM DBA_TABLES WHERE UPPER(owner) = UPPER(%s) AND (IOT_TYPE IS NUll OR IOT_TYPE='IOT')`, common.QuoteOracleString(schemaName)))
 	if err != nil {
 		return tables, err
 	}
 	for _, r := range res {
-		tables = append(tables, strings.ToUpper(r["TABLE_NAME"]))
+		tables = append(tables, r["TABLE_NAME"])
 	}
 
 	return tables, nil
 }
 
-func (o *Oracle) ScanOracleTableDecimalData(m Full, sourceDBCharset, targetDBCharset string, bigintStr, unsinBigintStr decimal.Decimal, callTimeout int64) ([]Scan, error) {
+// GetOracleTableColumn 获取表数据字典字段名，表名为数据字典表名，按大小写精确匹配
+func (o *Oracle) GetOracleTableColumn(ctx context.Context, schemaName, tableName string) ([]string, error) {
+	var columns []string
+	_, res, err := Query(ctx, o.OracleDB, fmt.Sprintf(`SELECT COLUMN_NAME FROM ALL_TAB_COLUMNS WHERE UPPER(OWNER) = UPPER(%s) AND TABLE_NAME = %s`, common.QuoteOracleString(schemaName), common.QuoteOracleString(tableName)))
+	if err != nil {
+		return columns, err
+	}
+	for _, r := range res {
+		columns = append(columns, r["COLUMN_NAME"])
+	}
+	return columns, nil
+}
+
+// GetOracleSchemaTableSize 获取 schema 表统计信息行数、块数以及段大小
+func (o *Oracle) GetOracleSchemaTableSize(ctx context.Context, schemaName string) ([]map[string]string, error) {
+	_, res, err := Query(ctx, o.OracleDB, fmt.Sprintf(`SELECT t.TABLE_NAME,
+       NVL(t.NUM_ROWS, 0) NUM_ROWS,
+       NVL(t.BLOCKS, 0) BLOCKS,
+       NVL(s.BYTES, 0) BYTES,
+       NVL(TO_CHAR(t.LAST_ANALYZED, 'YYYY-MM-DD HH24:MI:SS'), 'UNKNOWN') LAST_ANALYZED
+  FROM DBA_TABLES t
+  LEFT JOIN (SELECT SEGMENT_NAME, SUM(BYTES) BYTES
+               FROM DBA_SEGMENTS
+              WHERE UPPER(OWNER) = UPPER(%s)
+                AND SEGMENT_TYPE LIKE 'TABLE%%'
+              GROUP BY SEGMENT_NAME) s
+    ON t.TABLE_NAME = s.SEGMENT_NAME
+ WHERE UPPER(t.OWNER) = UPPER(%s)
+   AND (t.IOT_TYPE IS NULL OR t.IOT_TYPE = 'IOT')`, common.QuoteOracleString(schemaName), common.QuoteOracleString(schemaName)))
+	if err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func (o *Oracle) ScanOracleTableDecimalAggregate(ctx context.Context, m Full, columns []CheckColumn, sourceDBCharset, targetDBCharset string, callTimeout int64) ([]Summary, error) {
 	var (
-		err         error
-		columnNames []string
-		columnTypes []string
+		aggrColumns []string
 		sqlStr      string
 
-		results []Scan
+		summaries []Summary
 	)
 
-	columnDetail := m.ColumnDetailT
-	convertUtf8Raw, err := common.CharsetConvert([]byte(columnDetail), targetDBCharset, common.CharsetUTF8MB4)
-	if err != nil {
-		return results, fmt.Errorf("column [%s] charset convert failed, %v", columnDetail, err)
+	if len(columns) == 0 {
+		return summaries, nil
 	}
 
-	convertTargetRaw, err := common.CharsetConvert(convertUtf8Raw, common.CharsetUTF8MB4, sourceDBCharset)
-	if err != nil {
-		return results, fmt.Errorf("column [%s] charset convert failed, %v", columnDetail, err)
+	// 字段别名按字段顺序编号，避免字段名过长超出 oracle 标识符长度限制
+	for i, c := range columns {
+		columnName, err := convertColumnName(c.ColumnNameS, targetDBCharset, sourceDBCharset)
+		if err != nil {
+			return summaries, err
+		}
+		columnName = common.QuoteOracleIdentifier(columnName)
+		aggrColumns = append(aggrColumns, fmt.Sprintf("MIN(%s) MIN_%d, MAX(%s) MAX_%d, COUNT(%s) COUNT_%d", columnName, i, columnName, i, columnName, i))
 	}
-	columnDetail = string(convertTargetRaw)
+	aggrColumns = append(aggrColumns, "COUNT(1) ROW_COUNT")
 
 	if strings.EqualFold(m.SQLHint, "") {
-		sqlStr = fmt.Sprintf("SELECT %v FROM %s.%s WHERE %v", columnDetail, m.SchemaNameT, m.TableNameT, m.ChunkDetailT)
+		sqlStr = fmt.Sprintf("SELECT %v FROM %s.%s WHERE %v", strings.Join(aggrColumns, ", "), common.QuoteOracleIdentifier(m.SchemaNameS), common.QuoteOracleIdentifier(m.TableNameS), m.ChunkDetailT)
 	} else {
-		sqlStr = fmt.Sprintf("SELECT %v %v FROM %s.%s WHERE %v", m.SQLHint, columnDetail, m.SchemaNameT, m.TableNameT, m.ChunkDetailT)
+		sqlStr = fmt.Sprintf("SELECT %v %v FROM %s.%s WHERE %v", m.SQLHint, strings.Join(aggrColumns, ", "), common.QuoteOracleIdentifier(m.SchemaNameS), common.QuoteOracleIdentifier(m.TableNameS), m.ChunkDetailT)
 	}
 
 	deadline := time.Now().Add(time.Duration(callTimeout) * time.Second)
 
-	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
 	defer cancel()
 
-	rows, err := o.OracleDB.QueryContext(ctx, sqlStr)
+	_, res, err := Query(ctx, o.OracleDB, sqlStr)
 	if err != nil {
-		return results, err
+		return summaries, err
 	}
-	defer rows.Close()
 
-	colTypes, err := rows.ColumnTypes()
-	if err != nil {
-		return results, fmt.Errorf("failed to csv get rows columnTypes: %v", err)
+	for _, r := range res {
+		rowCount, err := strconv.ParseInt(r["ROW_COUNT"], 10, 64)
+		if err != nil {
+			return summaries, fmt.Errorf("sql [%v] parse row count [%v] failed: %v", sqlStr, r["ROW_COUNT"], err)
+		}
+		for i, c := range columns {
+			notNullCount, err := strconv.ParseInt(r[fmt.Sprintf("COUNT_%d", i)], 10, 64)
+			if err != nil {
+				return summaries, fmt.Errorf("sql [%v] parse column [%v] count failed: %v", sqlStr, c.ColumnName, err)
+			}
+			summary := Summary{
+				RunID:        m.RunID,
+				SchemaNameS:  m.SchemaNameS,
+				SchemaNameT:  m.SchemaNameT,
+				TableNameT:   m.TableNameT,
+				ChunkID:      m.ID,
+				ChunkDetailT: m.ChunkDetailT,
+				ColumnName:   strings.ToUpper(c.ColumnName),
+				CheckType:    CheckTypeInteger,
+				NullCount:    rowCount - notNullCount,
+				NotNullCount: notNullCount,
+			}
+			// chunk 内字段数据全为 NULL 无取值范围
+			if notNullCount > 0 {
+				summary.MinValue = r[fmt.Sprintf("MIN_%d", i)]
+				summary.MaxValue = r[fmt.Sprintf("MAX_%d", i)]
+			}
+			summaries = append(summaries, summary)
+		}
 	}
 
-	for _, ct := range colTypes {
-		convertUtf8Raw, err = common.CharsetConvert([]byte(ct.Name()), sourceDBCharset, common.CharsetUTF8MB4)
+	return summaries, nil
+}
+
+func (o *Oracle) ScanOracleTableData(ctx context.Context, m Full, columns []CheckColumn, sourceDBCharset, targetDBCharset string, callTimeout int64) ([]Scan, []Summary, error) {
+	var (
+		err           error
+		selectColumns []string
+		sqlStr        string
+
+		results   []Scan
+		summaries []Summary
+	)
+
+	if len(columns) == 0 {
+		return results, summaries, nil
+	}
+
+	for _, c := range columns {
+		columnName, err := convertColumnName(c.ColumnNameS, targetDBCharset, sourceDBCharset)
 		if err != nil {
-			return results, fmt.Errorf("column [%s] charset convert failed, %v", ct.Name(), err)
+			return results, summaries, err
+		}
+		columnName = common.QuoteOracleIdentifier(columnName)
+		// 时间类型统一转换为带符号年份字符串，兼容公元前日期
+		if c.HasCheck(CheckTypeTemporal) {
+			columnName = fmt.Sprintf("TO_CHAR(CAST(%s AS TIMESTAMP),'SYYYY-MM-DD HH24:MI:SS.FF6') %s", columnName, columnName)
 		}
+		selectColumns = append(selectColumns, columnName)
+	}
+	selectColumns = append(selectColumns, "ROWID")
 
-		convertTargetRaw, err = common.CharsetConvert(convertUtf8Raw, common.CharsetUTF8MB4, targetDBCharset)
-		if err != nil {
-			return results, fmt.Errorf("column [%s] charset convert failed, %v", ct.Name(), err)
+	if strings.EqualFold(m.SQLHint, "") {
+		sqlStr = fmt.Sprintf("SELECT %v FROM %s.%s WHERE %v", strings.Join(selectColumns, ","), common.QuoteOracleIdentifier(m.SchemaNameS), common.QuoteOracleIdentifier(m.TableNameS), m.ChunkDetailT)
+	} else {
+		sqlStr = fmt.Sprintf("SELECT %v %v FROM %s.%s WHERE %v", m.SQLHint, strings.Join(selectColumns, ","), common.QuoteOracleIdentifier(m.SchemaNameS), common.QuoteOracleIdentifier(m.TableNameS), m.ChunkDetailT)
+	}
+
+	deadline := time.Now().Add(time.Duration(callTimeout) * time.Second)
+
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	rows, err := o.OracleDB.QueryContext(ctx, sqlStr)
+	if err != nil {
+		return results, summaries, err
+	}
+	defer rows.Close()
+
+	// 字段各校验类型统计
+	stats := make([]map[string]*checkStat, len(columns))
+	for i, c := range columns {
+		stats[i] = make(map[string]*checkStat)
+		for _, t := range c.Checks {
+			stats[i][t] = newCheckStat(sourceDBCharset, targetDBCharset)
 		}
-		columnNames = append(columnNames, string(convertTargetRaw))
-		columnTypes = append(columnTypes, ct.ScanType().String())
 	}
 
-	// 数据 SCAN
-	columnNums := len(columnNames)
+	// 数据 SCAN，末尾字段为 ROWID
+	columnNums := len(selectColumns)
 	rawResult := make([][]byte, columnNums)
 	dest := make([]interface{}, columnNums)
 	for i := range rawResult {
@@ -263,88 +364,63 @@ func (o *Oracle) ScanOracleTableDecimalData(m Full, sourceDBCharset, targetDBCha
 	for rows.Next() {
 		err = rows.Scan(dest...)
 		if err != nil {
-			return results, err
+			return results, summaries, err
 		}
 
-		var (
-			rowid   string
-			columns []*Column
-		)
-
-		for i, raw := range rawResult {
-			switch columnTypes[i] {
-			case "godror.Number":
-				if raw == nil {
-					columns = append(columns, &Column{
-						ColumnName:           columnNames[i],
-						ColumnValue:          fmt.Sprintf("%v", `NULL`),
-						ColumnBigint:         "UNKNOWN",
-						ColumnUnsingedBigint: "UNKNOWN",
-					})
-				} else if string(raw) == "" {
-					columns = append(columns, &Column{
-						ColumnName:           columnNames[i],
-						ColumnValue:          fmt.Sprintf("%v", `NULL`),
-						ColumnBigint:         "UNKNOWN",
-						ColumnUnsingedBigint: "UNKNOWN",
-					})
-				} else {
-					decimalStr, err := decimal.NewFromString(string(raw))
-					if err != nil {
-						return results, err
-					}
-
-					cmpBigint := decimalStr.Cmp(bigintStr)
-					// > BIGINT
-					if cmpBigint == 1 {
-						cmpUnsGigint := decimalStr.Cmp(unsinBigintStr)
-
-						// > UNSIGNED BIGINT
-						if cmpUnsGigint == 1 {
-							columns = append(columns, &Column{
-								ColumnName:           columnNames[i],
-								ColumnValue:          string(raw),
-								ColumnBigint:         fmt.Sprintf("> %s", bigintStr),
-								ColumnUnsingedBigint: fmt.Sprintf("> %s", unsinBigintStr),
-							})
-						} else {
-							// <= UNSIGNED BIGINT
-							columns = append(columns, &Column{
-								ColumnName:           columnNames[i],
-								ColumnValue:          string(raw),
-								ColumnBigint:         fmt.Sprintf("> %s", bigintStr),
-								ColumnUnsingedBigint: fmt.Sprintf("<= %s", unsinBigintStr),
-							})
-						}
-					}
+		var violations []Scan
+		for i, c := range columns {
+			for _, t := range c.Checks {
+				column, err := stats[i][t].check(c, t, rawResult[i])
+				if err != nil {
+					return results, summaries, fmt.Errorf("sql [%v] query meet panic data, column [%v] columnvalue [%v]: %v", sqlStr, c.ColumnName, string(rawResult[i]), err)
 				}
-			default:
-				if strings.EqualFold(columnNames[i], "ROWID") {
-					rowid = fmt.Sprintf("%v", string(raw))
-				} else {
-					return results, fmt.Errorf("sql [%v] query meet panic data, column [%v] columntype [%v] columnvalue [%v]", sqlStr, columnNames[i], columnTypes[i], string(raw))
+				if column != nil {
+					violations = append(violations, Scan{
+						RunID:         m.RunID,
+						SchemaNameS:   m.SchemaNameS,
+						SchemaNameT:   m.SchemaNameT,
+						TableNameT:    m.TableNameT,
+						SQLHint:       m.SQLHint,
+						ColumnDetailT: m.ColumnDetailT,
+						ChunkDetailT:  m.ChunkDetailT,
+						CheckType:     t,
+						Column:        column,
+					})
 				}
 			}
 		}
 
-		if len(columns) > 0 {
-			for _, c := range columns {
-				results = append(results, Scan{
-					SchemaNameT:   m.SchemaNameT,
-					TableNameT:    m.TableNameT,
-					SQLHint:       m.SQLHint,
-					ColumnDetailT: m.ColumnDetailT,
-					ChunkDetailT:  m.ChunkDetailT,
-					RowID:         rowid,
-					Column:        c,
-				})
+		if len(violations) > 0 {
+			rowid := string(rawResult[columnNums-1])
+			for _, v := range violations {
+				v.RowID = rowid
+				results = append(results, v)
 			}
 		}
 	}
 
 	if err = rows.Err(); err != nil {
-		return results, err
+		return results, summaries, err
+	}
+
+	for i, c := range columns {
+		for _, t := range c.Checks {
+			summaries = append(summaries, stats[i][t].summary(m, c, t))
+		}
 	}
 
-	return results, nil
+	return results, summaries, nil
+}
+
+// convertColumnName 字段名由目标端字符集转换为源端字符集
+func convertColumnName(columnName, targetDBCharset, sourceDBCharset string) (string, error) {
+	convertUtf8Raw, err := common.CharsetConvert([]byte(columnName), targetDBCharset, common.CharsetUTF8MB4)
+	if err != nil {
+		return columnName, fmt.Errorf("column [%s] charset convert failed, %v", columnName, err)
+	}
+	convertTargetRaw, err := common.CharsetConvert(convertUtf8Raw, common.CharsetUTF8MB4, sourceDBCharset)
+	if err != nil {
+		return columnName, fmt.Errorf("column [%s] charset convert failed, %v", columnName, err)
+	}
+	return string(convertTargetRaw), nil
 }
@@ -0,0 +1,81 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package database
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+type Profile struct {
+	ID               uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
+	RunID            uint   `gorm:"not null;index:idx_complex;comment:'运行编号'" json:"run_id"`
+	SchemaNameS      string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'源端 schema'" json:"schema_name_s"`
+	SchemaNameT      string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端 schema'" json:"schema_name_t"`
+	TableNameT       string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端表名'" json:"table_name_t"`
+	ColumnName       string `gorm:"type:varchar(300);not null;comment:'表字段名'" json:"column_name"`
+	CheckType        string `gorm:"type:varchar(30);comment:'字段校验类型'" json:"check_type"`
+	MinValue         string `gorm:"type:varchar(300);comment:'表字段最小值'" json:"min_value"`
+	MaxValue         string `gorm:"type:varchar(300);comment:'表字段最大值'" json:"max_value"`
+	NullCount        int64  `gorm:"not null;default:0;comment:'表字段 NULL 行数'" json:"null_count"`
+	NotNullCount     int64  `gorm:"not null;default:0;comment:'表字段非 NULL 行数'" json:"not_null_count"`
+	ViolateCount     int64  `gorm:"not null;default:0;comment:'表字段越界数据行数'" json:"violate_count"`
+	MaxIntegerDigits int    `gorm:"not null;default:0;comment:'表字段最大整数位数'" json:"max_integer_digits"`
+	MaxScale         int    `gorm:"not null;default:0;comment:'表字段最大小数位数'" json:"max_scale"`
+	MaxLength        int    `gorm:"not null;default:0;comment:'表字段最大字符长度'" json:"max_length"`
+	MaxByteLength    int    `gorm:"not null;default:0;comment:'表字段目标端字段字符集最大字节长度'" json:"max_byte_length"`
+	ChunkCount       int64  `gorm:"not null;default:0;comment:'表字段已扫描 chunk 数'" json:"chunk_count"`
+	*Meta            `gorm:"-" json:"-"`
+}
+
+func NewProfileModel(m *Meta) *Profile {
+	return &Profile{
+		Meta: m,
+	}
+}
+
+func (rw *Profile) ParseSchemaTable() (string, error) {
+	stmt := &gorm.Statement{DB: rw.GormDB}
+	err := stmt.Parse(rw)
+	if err != nil {
+		return "", fmt.Errorf("parse struct [Profile] get table_name failed: %v", err)
+	}
+	return stmt.Schema.Table, nil
+}
+
+func (rw *Profile) DetailProfile(ctx context.Context, detailS *Profile) ([]Profile, error) {
+	var dsMetas []Profile
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return dsMetas, err
+	}
+	if err := rw.DB(ctx).Where(detailS).Find(&dsMetas).Error; err != nil {
+		return dsMetas, fmt.Errorf("detail table [%s] record failed: %v", table, err)
+	}
+	return dsMetas, nil
+}
+
+func (rw *Profile) BatchCreateProfile(ctx context.Context, createS []Profile, batchSize int) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err := rw.DB(ctx).CreateInBatches(createS, batchSize).Error; err != nil {
+		return fmt.Errorf("batch create table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package database
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/scan/config"
+	"gorm.io/gorm"
+	"time"
+)
+
+// 运行状态
+const (
+	RunStatusRunning  = "RUNNING"
+	RunStatusSuccess  = "SUCCESS"
+	RunStatusFailed   = "FAILED"
+	RunStatusCanceled = "CANCELED"
+)
+
+// Run 运行记录，init、split 阶段生成新运行编号，wait、full、scan、summary、statistics、violation、profile 按运行编号区分
+type Run struct {
+	ID           uint       `gorm:"primary_key;autoIncrement;comment:'运行编号'" json:"id"`
+	SchemaDetail string     `gorm:"type:varchar(768);not null;index:idx_schema;comment:'源端 schema 与目标端 schema 映射'" json:"schema_detail"`
+	Command      string     `gorm:"type:varchar(30);comment:'生成运行编号的子命令，空表示全部阶段'" json:"command"`
+	ConfigDetail string     `gorm:"type:longtext;comment:'运行配置快照'" json:"config_detail"`
+	StartTime    time.Time  `gorm:"not null;comment:'运行开始时间'" json:"start_time"`
+	EndTime      *time.Time `gorm:"comment:'运行结束时间'" json:"end_time"`
+	InitCost     string     `gorm:"type:varchar(100);comment:'init 阶段耗时'" json:"init_cost"`
+	SplitCost    string     `gorm:"type:varchar(100);comment:'split 阶段耗时'" json:"split_cost"`
+	ScanCost     string     `gorm:"type:varchar(100);comment:'scan 阶段耗时，断点续扫记录最近一次耗时'" json:"scan_cost"`
+	ReportCost   string     `gorm:"type:varchar(100);comment:'report 阶段耗时'" json:"report_cost"`
+	RunStatus    string     `gorm:"type:varchar(30);not null;comment:'运行状态'" json:"run_status"`
+	ErrorDetail  string     `gorm:"type:longtext;comment:'运行失败错误信息'" json:"error_detail"`
+	*Meta        `gorm:"-" json:"-"`
+}
+
+func NewRunModel(m *Meta) *Run {
+	return &Run{
+		Meta: m,
+	}
+}
+
+func (rw *Run) ParseSchemaTable() (string, error) {
+	stmt := &gorm.Statement{DB: rw.GormDB}
+	err := stmt.Parse(rw)
+	if err != nil {
+		return "", fmt.Errorf("parse struct [Run] get table_name failed: %v", err)
+	}
+	return stmt.Schema.Table, nil
+}
+
+func (rw *Run) CreateRun(ctx context.Context, createS *Run) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err = rw.DB(ctx).Create(createS).Error; err != nil {
+		return fmt.Errorf("create table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
+
+// GetRun 获取 schema 映射指定运行编号记录，运行编号为 0 获取最近一次运行记录，不包含仅执行 init 子命令的运行记录
+func (rw *Run) GetRun(ctx context.Context, schemaDetail string, runID uint) (Run, error) {
+	var run Run
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return run, err
+	}
+	db := rw.DB(ctx).Where("schema_detail = ?", schemaDetail)
+	if runID > 0 {
+		db = db.Where("id = ?", runID)
+	} else {
+		db = db.Where("command <> ?", config.CommandInit)
+	}
+	if err = db.Order("id DESC").Limit(1).Find(&run).Error; err != nil {
+		return run, fmt.Errorf("get table [%s] record failed: %v", table, err)
+	}
+	if run.ID == 0 {
+		return run, fmt.Errorf("get table [%s] schema [%s] run [%d] record not found, please run split subcommand", table, schemaDetail, runID)
+	}
+	return run, nil
+}
+
+func (rw *Run) UpdateRun(ctx context.Context, detailS *Run, updates map[string]interface{}) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err = rw.DB(ctx).Model(Run{}).Where("id = ?", detailS.ID).Updates(updates).Error; err != nil {
+		return fmt.Errorf("update table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
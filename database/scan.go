@@ -19,15 +19,19 @@ import (
 	"context"
 	"fmt"
 	"gorm.io/gorm"
+	"strings"
 )
 
 type Scan struct {
 	ID            uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
+	RunID         uint   `gorm:"not null;index:idx_complex;comment:'运行编号'" json:"run_id"`
+	SchemaNameS   string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'源端 schema'" json:"schema_name_s"`
 	SchemaNameT   string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端 schema'" json:"schema_name_t"`
 	TableNameT    string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端表名'" json:"table_name_t"`
 	SQLHint       string `gorm:"type:varchar(300);comment:'sql hint'" json:"sql_hint"`
 	ColumnDetailT string `gorm:"type:longtext;comment:'源端查询字段信息'" json:"column_detail_t"`
 	ChunkDetailT  string `gorm:"type:varchar(300);not null;comment:'表 chunk 切分信息'" json:"chunk_detail_t"`
+	CheckType     string `gorm:"type:varchar(30);comment:'字段校验类型'" json:"check_type"`
 	RowID         string `gorm:"type:varchar(300);not null;index:idx_complex;comment:'表异常数据所在行 rowid'" json:"row_id"`
 	*Column
 	*Meta `gorm:"-" json:"-"`
@@ -36,8 +40,10 @@ type Scan struct {
 type Column struct {
 	ColumnName           string `gorm:"type:varchar(300);not null;comment:'表异常数据所在行 rowid 字段名'" json:"column_name"`
 	ColumnValue          string `gorm:"type:varchar(300);not null;comment:'表异常数据所在行 rowid 字段值'" json:"column_value"`
+	ColumnBoundary       string `gorm:"type:varchar(30);comment:'表异常数据所在行 rowid 字段越界方向, eg: MAX、MIN、PRECISION、SCALE、LENGTH'" json:"column_boundary"`
 	ColumnBigint         string `gorm:"type:varchar(300);not null;comment:'表异常数据所在行 rowid 字段是否超过 bigint, eg: UNKNOWN、LESS、MORE'" json:"column_bigint"`
 	ColumnUnsingedBigint string `gorm:"type:varchar(300);not null;comment:'表异常数据所在行 rowid 字段是否超过 unsinged bigint, eg: UNKNOWN、LESS、MORE'" json:"column_unsinged_bigint"`
+	ColumnSuggest        string `gorm:"type:varchar(100);comment:'表异常数据所在行 rowid 字段建议类型, eg: UNKNOWN、DATETIME'" json:"column_suggest"`
 }
 
 func NewScanModel(m *Meta) *Scan {
@@ -77,3 +83,21 @@ func (rw *Scan) BatchCreateScanResult(ctx context.Context, createS []Scan, batch
 	}
 	return nil
 }
+
+func (rw *Scan) DeleteScanResult(ctx context.Context, deleteS *Scan) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err = rw.DB(ctx).
+		Where("run_id = ? AND schema_name_s = ? AND schema_name_t = ? AND table_name_t = ? AND chunk_detail_t = ?",
+			deleteS.RunID,
+			strings.ToUpper(deleteS.SchemaNameS),
+			strings.ToUpper(deleteS.SchemaNameT),
+			strings.ToUpper(deleteS.TableNameT),
+			deleteS.ChunkDetailT).
+		Delete(&Scan{}).Error; err != nil {
+		return fmt.Errorf("delete table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
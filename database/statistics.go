@@ -23,12 +23,16 @@ import (
 )
 
 type Statistics struct {
-	ID              uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
-	SchemaNameT     string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端 schema'" json:"schema_name_t"`
-	TableNameT      string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端表名'" json:"table_name_t"`
-	ModifyColumn    string `gorm:"type:longtext;comment:'目标端表字段信息满足条件可 modify'" json:"modify_column"`
-	NotModifyColumn string `gorm:"type:longtext;comment:'目标端表字段信息不满足条件不可 modify'" json:"not_modify_column"`
-	*Meta           `gorm:"-" json:"-"`
+	ID                uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
+	RunID             uint   `gorm:"not null;index:idx_complex;comment:'运行编号'" json:"run_id"`
+	SchemaNameS       string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'源端 schema'" json:"schema_name_s"`
+	SchemaNameT       string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端 schema'" json:"schema_name_t"`
+	TableNameT        string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端表名'" json:"table_name_t"`
+	ModifyColumn      string `gorm:"type:longtext;comment:'目标端表字段信息满足条件可 modify'" json:"modify_column"`
+	NotModifyColumn   string `gorm:"type:longtext;comment:'目标端表字段信息不满足条件不可 modify'" json:"not_modify_column"`
+	RollbackColumn    string `gorm:"type:longtext;comment:'目标端表字段 modify 回滚语句，恢复原字段定义'" json:"rollback_column"`
+	UnevaluatedColumn string `gorm:"type:longtext;comment:'目标端表字段 chunk 未全部扫描完成或者无扫描统计，未判定'" json:"unevaluated_column"`
+	*Meta             `gorm:"-" json:"-"`
 }
 
 func NewStatisticsModel(m *Meta) *Statistics {
@@ -0,0 +1,101 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package database
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"strings"
+)
+
+type Summary struct {
+	ID               uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
+	RunID            uint   `gorm:"not null;index:idx_complex;comment:'运行编号'" json:"run_id"`
+	SchemaNameS      string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'源端 schema'" json:"schema_name_s"`
+	SchemaNameT      string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端 schema'" json:"schema_name_t"`
+	TableNameT       string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端表名'" json:"table_name_t"`
+	ChunkID          uint   `gorm:"not null;comment:'表 chunk 编号'" json:"chunk_id"`
+	ChunkDetailT     string `gorm:"type:varchar(300);not null;comment:'表 chunk 切分信息'" json:"chunk_detail_t"`
+	ColumnName       string `gorm:"type:varchar(300);not null;index:idx_complex;comment:'表字段名'" json:"column_name"`
+	CheckType        string `gorm:"type:varchar(30);comment:'字段校验类型'" json:"check_type"`
+	MinValue         string `gorm:"type:varchar(300);comment:'表 chunk 字段最小值'" json:"min_value"`
+	MaxValue         string `gorm:"type:varchar(300);comment:'表 chunk 字段最大值'" json:"max_value"`
+	NullCount        int64  `gorm:"not null;default:0;comment:'表 chunk 字段 NULL 行数'" json:"null_count"`
+	NotNullCount     int64  `gorm:"not null;default:0;comment:'表 chunk 字段非 NULL 行数'" json:"not_null_count"`
+	ViolateCount     int64  `gorm:"not null;default:0;comment:'表 chunk 字段越界数据行数'" json:"violate_count"`
+	MaxIntegerDigits int    `gorm:"not null;default:0;comment:'表 chunk 字段最大整数位数'" json:"max_integer_digits"`
+	MaxScale         int    `gorm:"not null;default:0;comment:'表 chunk 字段最大小数位数'" json:"max_scale"`
+	MaxLength        int    `gorm:"not null;default:0;comment:'表 chunk 字段最大字符长度'" json:"max_length"`
+	MaxByteLength    int    `gorm:"not null;default:0;comment:'表 chunk 字段目标端字段字符集最大字节长度'" json:"max_byte_length"`
+	*Meta            `gorm:"-" json:"-"`
+}
+
+func NewSummaryModel(m *Meta) *Summary {
+	return &Summary{
+		Meta: m,
+	}
+}
+
+func (rw *Summary) ParseSchemaTable() (string, error) {
+	stmt := &gorm.Statement{DB: rw.GormDB}
+	err := stmt.Parse(rw)
+	if err != nil {
+		return "", fmt.Errorf("parse struct [Summary] get table_name failed: %v", err)
+	}
+	return stmt.Schema.Table, nil
+}
+
+func (rw *Summary) DetailSummaryResult(ctx context.Context, detailS *Summary) ([]Summary, error) {
+	var dsMetas []Summary
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return dsMetas, err
+	}
+	if err := rw.DB(ctx).Where(detailS).Find(&dsMetas).Error; err != nil {
+		return dsMetas, fmt.Errorf("detail table [%s] record failed: %v", table, err)
+	}
+	return dsMetas, nil
+}
+
+func (rw *Summary) BatchCreateSummaryResult(ctx context.Context, createS []Summary, batchSize int) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err := rw.DB(ctx).CreateInBatches(createS, batchSize).Error; err != nil {
+		return fmt.Errorf("batch create table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
+
+func (rw *Summary) DeleteSummaryResult(ctx context.Context, deleteS *Summary) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err = rw.DB(ctx).
+		Where("run_id = ? AND schema_name_s = ? AND schema_name_t = ? AND table_name_t = ? AND chunk_detail_t = ?",
+			deleteS.RunID,
+			strings.ToUpper(deleteS.SchemaNameS),
+			strings.ToUpper(deleteS.SchemaNameT),
+			strings.ToUpper(deleteS.TableNameT),
+			deleteS.ChunkDetailT).
+		Delete(&Summary{}).Error; err != nil {
+		return fmt.Errorf("delete table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
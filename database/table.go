@@ -16,12 +16,15 @@ limitations under the License.
 package database
 
 import (
+	"context"
 	"fmt"
-	"strings"
+	"strconv"
+
+	"github.com/wentaojin/scan/common"
 )
 
-func (m *MySQL) GetMySQLTables(schemaName string) ([]string, error) {
-	_, res, err := Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES where UPPER(TABLE_SCHEMA) = '%s' AND TABLE_TYPE = 'BASE TABLE'`, strings.ToUpper(schemaName)))
+func (m *MySQL) GetMySQLTables(ctx context.Context, schemaName string) ([]string, error) {
+	_, res, err := Query(ctx, m.MySQLDB, fmt.Sprintf(`SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES where UPPER(TABLE_SCHEMA) = UPPER(%s) AND TABLE_TYPE = 'BASE TABLE'`, common.QuoteMySQLString(schemaName)))
 	if err != nil {
 		return []string{}, err
 	}
@@ -35,14 +38,43 @@ func (m *MySQL) GetMySQLTables(schemaName string) ([]string, error) {
 	return tables, nil
 }
 
-func (m *MySQL) GetMySQLTableColumn(schemaName, tableName string) ([]map[string]string, error) {
+// GetMySQLVersion 获取目标端版本，tidb eg: 5.7.25-TiDB-v6.5.0
+func (m *MySQL) GetMySQLVersion(ctx context.Context) (string, error) {
+	_, res, err := Query(ctx, m.MySQLDB, `SELECT VERSION() AS VERSION`)
+	if err != nil {
+		return "", err
+	}
+	if len(res) == 0 {
+		return "", fmt.Errorf("mysql database version query empty")
+	}
+	return res[0]["VERSION"], nil
+}
+
+// GetMySQLTimeZoneOffset 获取目标端会话 time_zone 当前相对 UTC 偏移秒数，timestamp 按该时区换算 UTC 存储
+func (m *MySQL) GetMySQLTimeZoneOffset(ctx context.Context) (int64, error) {
+	_, res, err := Query(ctx, m.MySQLDB, `SELECT TIMESTAMPDIFF(SECOND, UTC_TIMESTAMP(), NOW()) AS OFFSET`)
+	if err != nil {
+		return 0, err
+	}
+	if len(res) == 0 {
+		return 0, fmt.Errorf("mysql database time_zone offset query empty")
+	}
+	return strconv.ParseInt(res[0]["OFFSET"], 10, 64)
+}
+
+func (m *MySQL) GetMySQLTableColumn(ctx context.Context, schemaName, tableName string) ([]map[string]string, error) {
 	var (
 		res []map[string]string
 		err error
 	)
 
-	_, res, err = Query(m.Ctx, m.MySQLDB, fmt.Sprintf(`SELECT COLUMN_NAME,
+	_, res, err = Query(ctx, m.MySQLDB, fmt.Sprintf(`SELECT TABLE_SCHEMA,
+		TABLE_NAME,
+		COLUMN_NAME,
 		DATA_TYPE,
+		COLUMN_TYPE,
+		IFNULL(EXTRA,'') EXTRA,
+		IFNULL(GENERATION_EXPRESSION,'') GENERATION_EXPRESSION,
 		IFNULL(CHARACTER_MAXIMUM_LENGTH,0) DATA_LENGTH,
 		IFNULL(NUMERIC_SCALE,0) DATA_SCALE,
 		IFNULL(NUMERIC_PRECISION,0) DATA_PRECISION,
@@ -53,9 +85,9 @@ func (m *MySQL) GetMySQLTableColumn(schemaName, tableName string) ([]map[string]
 		IFNULL(CHARACTER_SET_NAME,'UNKNOWN') CHARACTER_SET_NAME,
 		IFNULL(COLLATION_NAME,'UNKNOWN') COLLATION_NAME
  FROM information_schema.COLUMNS
- WHERE UPPER(TABLE_SCHEMA) = UPPER('%s')
-   AND UPPER(TABLE_NAME) = UPPER('%s')
- ORDER BY ORDINAL_POSITION`, schemaName, tableName))
+ WHERE UPPER(TABLE_SCHEMA) = UPPER(%s)
+   AND UPPER(TABLE_NAME) = UPPER(%s)
+ ORDER BY ORDINAL_POSITION`, common.QuoteMySQLString(schemaName), common.QuoteMySQLString(tableName)))
 
 	if err != nil {
 		return res, err
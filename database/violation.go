@@ -0,0 +1,78 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package database
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+)
+
+type Violation struct {
+	ID               uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
+	RunID            uint   `gorm:"not null;index:idx_complex;comment:'运行编号'" json:"run_id"`
+	SchemaNameS      string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'源端 schema'" json:"schema_name_s"`
+	SchemaNameT      string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端 schema'" json:"schema_name_t"`
+	TableNameT       string `gorm:"type:varchar(100);not null;index:idx_complex;comment:'目标端表名'" json:"table_name_t"`
+	ColumnName       string `gorm:"type:varchar(300);not null;comment:'表字段名'" json:"column_name"`
+	CheckType        string `gorm:"type:varchar(30);comment:'字段校验类型'" json:"check_type"`
+	ViolateCount     int64  `gorm:"not null;default:0;comment:'表字段越界数据行数'" json:"violate_count"`
+	SampleCount      int64  `gorm:"not null;default:0;comment:'表字段越界数据 rowid 样本记录数'" json:"sample_count"`
+	FirstChunkDetail string `gorm:"type:varchar(300);comment:'表字段首个出现越界数据 chunk'" json:"first_chunk_detail"`
+	LastChunkDetail  string `gorm:"type:varchar(300);comment:'表字段最后出现越界数据 chunk'" json:"last_chunk_detail"`
+	ScannedChunks    int64  `gorm:"not null;default:0;comment:'表字段已扫描 chunk 数'" json:"scanned_chunks"`
+	TotalChunks      int64  `gorm:"not null;default:0;comment:'表 chunk 总数'" json:"total_chunks"`
+	PartialCount     bool   `gorm:"not null;default:false;comment:'越界数据行数是否为部分 chunk 统计，已扫描 chunk 数小于 chunk 总数时为下限值'" json:"partial_count"`
+	*Meta            `gorm:"-" json:"-"`
+}
+
+func NewViolationModel(m *Meta) *Violation {
+	return &Violation{
+		Meta: m,
+	}
+}
+
+func (rw *Violation) ParseSchemaTable() (string, error) {
+	stmt := &gorm.Statement{DB: rw.GormDB}
+	err := stmt.Parse(rw)
+	if err != nil {
+		return "", fmt.Errorf("parse struct [Violation] get table_name failed: %v", err)
+	}
+	return stmt.Schema.Table, nil
+}
+
+func (rw *Violation) DetailViolation(ctx context.Context, detailS *Violation) ([]Violation, error) {
+	var dsMetas []Violation
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return dsMetas, err
+	}
+	if err := rw.DB(ctx).Where(detailS).Find(&dsMetas).Error; err != nil {
+		return dsMetas, fmt.Errorf("detail table [%s] record failed: %v", table, err)
+	}
+	return dsMetas, nil
+}
+
+func (rw *Violation) BatchCreateViolation(ctx context.Context, createS []Violation, batchSize int) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err := rw.DB(ctx).CreateInBatches(createS, batchSize).Error; err != nil {
+		return fmt.Errorf("batch create table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
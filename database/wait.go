@@ -21,11 +21,16 @@ import (
 	"gorm.io/gorm"
 )
 
+// Wait 待扫描表以及字段，按运行编号保存快照，历史运行编号报告使用当时的字段
 type Wait struct {
 	ID            uint   `gorm:"primary_key;autoIncrement;comment:'自增编号'" json:"id"`
-	SchemaNameT   string `gorm:"type:varchar(100);not null;index:idx_dbtype_st_map,unique;comment:'目标端 schema'" json:"schema_name_t"`
-	TableNameS    string `gorm:"type:varchar(100);not null;index:idx_dbtype_st_map,unique;comment:'源端表名'" json:"table_name_s"`
-	ColumnDetailS string `gorm:"type:longtext;not null;" json:"column_detail_s"`
+	RunID         uint   `gorm:"not null;index:idx_run_schema_table,unique;comment:'运行编号'" json:"run_id"`
+	SchemaNameS   string `gorm:"type:varchar(100);not null;index:idx_run_schema_table,unique;comment:'源端 schema'" json:"schema_name_s"`
+	SchemaNameT   string `gorm:"type:varchar(100);not null;index:idx_run_schema_table,unique;comment:'目标端 schema'" json:"schema_name_t"`
+	TableNameS    string `gorm:"type:varchar(100);not null;comment:'源端表名'" json:"table_name_s"`
+	TableNameT    string `gorm:"type:varchar(100);not null;index:idx_run_schema_table,unique;comment:'目标端表名'" json:"table_name_t"`
+	ColumnDetailS string `gorm:"type:longtext;not null;comment:'源端查询字段信息'" json:"column_detail_s"`
+	ColumnDetailT string `gorm:"type:longtext;comment:'目标端校验字段信息'" json:"column_detail_t"`
 	*Meta         `gorm:"-" json:"-"`
 }
 
@@ -64,3 +69,27 @@ func (rw *Wait) DetailWaitSyncMeta(ctx context.Context, detailS *Wait) ([]Wait,
 	}
 	return dsMetas, nil
 }
+
+// DetailLatestWaitRunID 获取 schema 映射最近一次记录待扫描表的运行编号，不存在返回 false
+func (rw *Wait) DetailLatestWaitRunID(ctx context.Context, detailS *Wait) (uint, bool, error) {
+	var latest Wait
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return 0, false, err
+	}
+	if err = rw.DB(ctx).Where(detailS).Order("run_id DESC").Limit(1).Find(&latest).Error; err != nil {
+		return 0, false, fmt.Errorf("detail table [%s] record failed: %v", table, err)
+	}
+	return latest.RunID, latest.ID > 0, nil
+}
+
+func (rw *Wait) DeleteWaitSyncMeta(ctx context.Context, deleteS *Wait) error {
+	table, err := rw.ParseSchemaTable()
+	if err != nil {
+		return err
+	}
+	if err = rw.DB(ctx).Where("run_id = ? AND schema_name_s = ? AND schema_name_t = ?", deleteS.RunID, deleteS.SchemaNameS, deleteS.SchemaNameT).Delete(&Wait{}).Error; err != nil {
+		return fmt.Errorf("delete table [%s] record failed: %v", table, err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/wentaojin/scan/config"
+	"github.com/wentaojin/scan/database"
+	"github.com/xxjwxc/gowp/workpool"
+	"go.uber.org/zap"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// tablePlan 单表扫描计划
+type tablePlan struct {
+	pair         config.SchemaPair
+	tableName    string
+	tableNameS   string
+	numRows      int64
+	blocks       int64
+	bytes        int64
+	lastAnalyzed string
+	chunks       int64
+	columns      []database.CheckColumn
+}
+
+// Plan 输出待扫描表、字段以及基于 oracle 统计信息估算的 chunk 数、会话数以及耗时，不创建 chunk 任务以及扫描数据
+func Plan(ctx context.Context, dbS *database.MySQL, dbT *database.Oracle, cfg *config.Config) error {
+	sTime := time.Now()
+
+	var plans []*tablePlan
+	for _, pair := range cfg.SchemaPairs() {
+		mysqlTables, err := dbS.GetMySQLTables(ctx, pair.MySQLSchema)
+		if err != nil {
+			return err
+		}
+		sizes, err := dbT.GetOracleSchemaTableSize(ctx, pair.OracleSchema)
+		if err != nil {
+			return err
+		}
+		var oraTables []string
+		oraSizes := make(map[string]map[string]string)
+		for _, s := range sizes {
+			oraTables = append(oraTables, s["TABLE_NAME"])
+			oraSizes[s["TABLE_NAME"]] = s
+		}
+
+		// 与 filterTasks 一致的表名匹配规则
+		for _, t := range mysqlTables {
+			tableNameS, ok := matchOracleTable(oraTables, cfg.MappingConfig.OracleTableName(t))
+			if !ok || !cfg.FilterConfig.MatchTable(t) {
+				continue
+			}
+			s := oraSizes[tableNameS]
+			p := &tablePlan{
+				pair:         pair,
+				tableName:    strings.ToUpper(t),
+				tableNameS:   tableNameS,
+				lastAnalyzed: s["LAST_ANALYZED"],
+			}
+			if p.numRows, err = strconv.ParseInt(s["NUM_ROWS"], 10, 64); err != nil {
+				return err
+			}
+			if p.blocks, err = strconv.ParseInt(s["BLOCKS"], 10, 64); err != nil {
+				return err
+			}
+			if p.bytes, err = strconv.ParseInt(s["BYTES"], 10, 64); err != nil {
+				return err
+			}
+			// 按行切分 chunk，无统计信息或者空表切分为单个 chunk
+			p.chunks = 1
+			if cfg.AppConfig.ChunkSize > 0 && p.numRows > int64(cfg.AppConfig.ChunkSize) {
+				p.chunks = (p.numRows + int64(cfg.AppConfig.ChunkSize) - 1) / int64(cfg.AppConfig.ChunkSize)
+			}
+			plans = append(plans, p)
+		}
+	}
+
+	g := workpool.New(cfg.AppConfig.InitThread)
+	for _, tp := range plans {
+		p := tp
+		g.Do(func() error {
+			columns, err := dbS.GetMySQLTableColumn(ctx, p.pair.MySQLSchema, p.tableName)
+			if err != nil {
+				return err
+			}
+			p.columns, err = newCheckColumns(cfg, p.tableName, columns)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	var (
+		tables                 int
+		totalRows, totalBytes  int64
+		totalChunks, maxChunks int64
+		tableThread, sqlThread = cfg.AppConfig.TableThread, cfg.AppConfig.SQLThread
+	)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tMYSQL TABLE\tNUM_ROWS\tBLOCKS\tSIZE(MB)\tLAST_ANALYZED\tCHUNKS\tCOLUMNS")
+	for _, p := range plans {
+		// 无待校验字段的表 init 阶段不记录，不参与扫描
+		if len(p.columns) == 0 {
+			continue
+		}
+		var columns []string
+		for _, c := range p.columns {
+			columns = append(columns, fmt.Sprintf("%s(%s)", strings.ToUpper(c.ColumnName), strings.Join(c.Checks, "|")))
+		}
+		fmt.Fprintf(w, "%s.%s\t%s.%s\t%d\t%d\t%.2f\t%s\t%d\t%s\n", p.pair.OracleSchema, p.tableNameS, p.pair.MySQLSchema, p.tableName, p.numRows, p.blocks, float64(p.bytes)/1024/1024, p.lastAnalyzed, p.chunks, strings.Join(columns, ","))
+
+		tables++
+		totalRows += p.numRows
+		totalBytes += p.bytes
+		totalChunks += p.chunks
+		if p.chunks > maxChunks {
+			maxChunks = p.chunks
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	// 表级并发 table-thread，单表 chunk 并发 sql-thread，实际并发受 chunk 数限制
+	sessions := int64(tableThread * sqlThread)
+	parallel := sessions
+	if totalChunks < parallel {
+		parallel = totalChunks
+	}
+	if maxChunks*int64(tableThread) < parallel {
+		parallel = maxChunks * int64(tableThread)
+	}
+	var estimate time.Duration
+	if cfg.AppConfig.EstimateRows > 0 && parallel > 0 {
+		estimate = time.Duration(float64(totalRows) / float64(int64(cfg.AppConfig.EstimateRows)*parallel) * float64(time.Second))
+	}
+
+	fmt.Fprintf(os.Stdout, "\nTABLES: %d  ROWS: %d  SIZE(MB): %.2f  CHUNKS: %d  CHUNK-SIZE: %d\n",
+		tables, totalRows, float64(totalBytes)/1024/1024, totalChunks, cfg.AppConfig.ChunkSize)
+	fmt.Fprintf(os.Stdout, "SESSIONS: %d (table-thread %d x sql-thread %d), SPLIT SESSIONS: %d (init-thread)\n",
+		sessions, tableThread, sqlThread, cfg.AppConfig.InitThread)
+	fmt.Fprintf(os.Stdout, "ESTIMATED SCAN DURATION: %s (estimate-rows-per-second %d, parallel %d)\n",
+		estimate.Round(time.Second).String(), cfg.AppConfig.EstimateRows, parallel)
+
+	zap.L().Info("plan oracle database schema tables success", zap.String("schema", cfg.SchemaDetail()), zap.Int("tables", tables), zap.String("cost", time.Now().Sub(sTime).String()))
+	return nil
+}
@@ -42,10 +42,11 @@ func main() {
 
 	logger.NewZapLogger(cfg)
 
+	// 退出信号取消 ctx，进行中 chunk 重置为 WAITING 并删除 oracle chunk 任务后退出
+	ctx, cancel := context.WithCancel(context.Background())
 	signal.SetupSignalHandler(func() {
-		os.Exit(1)
+		cancel()
 	})
-	ctx := context.Background()
 	if err := Run(ctx, cfg); err != nil {
 		zap.L().Fatal("server run failed", zap.Error(err))
 	}
@@ -60,160 +61,385 @@ func Run(ctx context.Context, cfg *config.Config) error {
 	if err != nil {
 		return err
 	}
+	// plan 子命令仅读取 mysql、oracle 字典信息，不访问元数据库
+	if cfg.Command == config.CommandPlan {
+		oracleDB, err := database.NewOracleDBEngine(ctx, cfg.OracleConfig)
+		if err != nil {
+			return err
+		}
+		return Plan(ctx, mysqldb, oracleDB, cfg)
+	}
+
 	metaDB, err := database.NewMetaDBEngine(ctx, cfg.MetaConfig)
 	if err != nil {
 		return err
 	}
-	oracleDB, err := database.NewOracleDBEngine(ctx, cfg.OracleConfig)
+
+	mTime := time.Now()
+	err = metaDB.MigrateTables()
 	if err != nil {
 		return err
 	}
-	zap.L().Info("create database connect success", zap.String("cost", time.Now().Sub(sTime).String()))
+	zap.L().Info("migrate meta tables success", zap.String("cost", time.Now().Sub(mTime).String()))
+
+	// 无需运行记录的子命令
+	switch cfg.Command {
+	case config.CommandStatus:
+		return Status(ctx, metaDB, cfg)
+	case config.CommandReset:
+		return Reset(ctx, metaDB, cfg)
+	}
 
-	err = metaDB.DB(ctx).Exec(fmt.Sprintf("DELETE FROM `%s`.`statistics` WHERE schema_name_t = '%s'", cfg.MetaConfig.MetaSchema, strings.ToUpper(cfg.OracleConfig.Schema))).Error
+	// init、split 阶段生成新运行编号，保留历史运行结果，scan、report 阶段沿用指定或者最近一次运行编号
+	var run database.Run
+	runInit := cfg.Command == config.CommandInit || (cfg.Command == "" && !cfg.AppConfig.SkipInit)
+	split := cfg.Command == config.CommandSplit || (cfg.Command == "" && !cfg.AppConfig.SkipSplit)
+	if cfg.Command == config.CommandInit || split {
+		run = database.Run{
+			SchemaDetail: cfg.SchemaDetail(),
+			Command:      cfg.Command,
+			ConfigDetail: cfg.String(),
+			StartTime:    sTime,
+			RunStatus:    database.RunStatusRunning,
+		}
+		err = database.NewRunModel(metaDB).CreateRun(ctx, &run)
+	} else {
+		run, err = database.NewRunModel(metaDB).GetRun(ctx, cfg.SchemaDetail(), cfg.RunID)
+	}
+	if err != nil {
+		return err
+	}
+	// 运行编号沿用时仅更新状态，保留生成运行编号的子命令
+	err = database.NewRunModel(metaDB).UpdateRun(ctx, &run, map[string]interface{}{
+		"RunStatus": database.RunStatusRunning,
+	})
 	if err != nil {
 		return err
 	}
-	zap.L().Warn("delete meta database table finished", zap.String("schema", cfg.MetaConfig.MetaSchema), zap.String("tables", "statistics"), zap.String("status", "success"))
+	zap.L().Info("scan database program run", zap.String("schema", run.SchemaDetail), zap.Uint("run", run.ID), zap.Bool("new run", cfg.Command == config.CommandInit || split))
+
+	updates := make(map[string]interface{})
+	err = func() error {
+		if runInit {
+			tTime := time.Now()
+			if err := Init(ctx, metaDB, mysqldb, cfg, run.ID); err != nil {
+				return err
+			}
+			updates["InitCost"] = time.Now().Sub(tTime).String()
+		} else if cfg.Command == "" {
+			zap.L().Warn("skip meta database init table stage", zap.String("schema", cfg.MetaConfig.MetaSchema), zap.Bool("skip-init", cfg.AppConfig.SkipInit))
+		}
+		if cfg.Command == config.CommandInit {
+			return nil
+		}
 
-	if !cfg.AppConfig.SkipInit {
-		err = Init(ctx, metaDB, mysqldb, cfg)
+		// report 子命令基于运行记录已有 chunk 的表以及 scan 结果生成报告，无需访问 oracle
+		var (
+			oracleDB *database.Oracle
+			tasks    []database.Wait
+			err      error
+		)
+		if cfg.Command == config.CommandReport {
+			tasks, err = reportTasks(ctx, metaDB, cfg, run.ID)
+		} else {
+			oracleDB, err = database.NewOracleDBEngine(ctx, cfg.OracleConfig)
+			if err != nil {
+				return err
+			}
+			zap.L().Info("create database connect success", zap.String("cost", time.Now().Sub(sTime).String()))
+			tasks, err = filterTasks(ctx, metaDB, oracleDB, cfg, run.ID)
+		}
 		if err != nil {
 			return err
 		}
-		zap.L().Warn("skip meta database init table stage", zap.String("schema", cfg.MetaConfig.MetaSchema), zap.Bool("skip-init", cfg.AppConfig.SkipInit))
-	}
 
-	metaTables, err := database.NewWaitModel(metaDB).DetailWaitSyncMeta(ctx, &database.Wait{
-		SchemaNameT: strings.ToUpper(cfg.MySQLConfig.Schema),
-	})
-	if err != nil {
-		return err
-	}
+		if split {
+			tTime := time.Now()
+			if err := Split(ctx, metaDB, oracleDB, cfg, run.ID, tasks); err != nil {
+				return err
+			}
+			updates["SplitCost"] = time.Now().Sub(tTime).String()
+		}
+
+		if cfg.Command == config.CommandScan || cfg.Command == "" {
+			tTime := time.Now()
+			if err := Scan(ctx, metaDB, mysqldb, oracleDB, cfg, run.ID, tasks); err != nil {
+				return err
+			}
+			updates["ScanCost"] = time.Now().Sub(tTime).String()
+		}
 
-	// filter
-	fTime := time.Now()
-	oraTables, err := oracleDB.GetOracleSchemaTable(strings.ToUpper(cfg.OracleConfig.Schema))
+		if cfg.Command == config.CommandReport || cfg.Command == "" {
+			tTime := time.Now()
+			if err := deleteMetaTables(ctx, metaDB, cfg, run.ID, "statistics", "violation", "profile"); err != nil {
+				return err
+			}
+			if err := Statistics(ctx, metaDB, mysqldb, cfg, run.ID, tasks); err != nil {
+				return err
+			}
+			updates["ReportCost"] = time.Now().Sub(tTime).String()
+		}
+		return nil
+	}()
+
+	updates["EndTime"] = time.Now()
+	updates["RunStatus"] = database.RunStatusSuccess
+	updates["ErrorDetail"] = ""
+	switch {
+	case ctx.Err() != nil:
+		updates["RunStatus"] = database.RunStatusCanceled
+		updates["ErrorDetail"] = ctx.Err().Error()
+	case err != nil:
+		updates["RunStatus"] = database.RunStatusFailed
+		updates["ErrorDetail"] = err.Error()
+	}
+	// 程序中断 ctx 已取消，使用独立 context 记录运行结果
+	if uErr := database.NewRunModel(metaDB).UpdateRun(context.Background(), &run, updates); uErr != nil {
+		if err != nil {
+			zap.L().Error("update meta database run failed", zap.Uint("run", run.ID), zap.Error(uErr))
+			return err
+		}
+		return uErr
+	}
 	if err != nil {
 		return err
 	}
+	zap.L().Info("scan database program finished", zap.String("command", cfg.Command), zap.Uint("run", run.ID), zap.String("cost", time.Now().Sub(sTime).String()))
 
+	return nil
+}
+
+// filterTasks 获取各 schema 映射运行编号 init 阶段记录且 oracle 存在的表
+func filterTasks(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *config.Config, runID uint) ([]database.Wait, error) {
 	var tasks []database.Wait
-	for _, ora := range oraTables {
+	for _, pair := range cfg.SchemaPairs() {
+		metaTables, err := runWaitTables(ctx, dbM, runID, pair)
+		if err != nil {
+			return nil, err
+		}
+
+		fTime := time.Now()
+		oraTables, err := dbT.GetOracleSchemaTable(ctx, pair.OracleSchema)
+		if err != nil {
+			return nil, err
+		}
+
+		var schemaTasks []database.Wait
 		for _, t := range metaTables {
-			if strings.EqualFold(t.TableNameS, ora) {
-				tasks = append(tasks, t)
+			// oracle SQL 引号标识符区分大小写，使用数据字典表名
+			ora, ok := matchOracleTable(oraTables, t.TableNameS)
+			if ok && cfg.FilterConfig.MatchTable(t.TableNameT) {
+				t.TableNameS = ora
+				schemaTasks = append(schemaTasks, t)
 			}
 		}
+		tasks = append(tasks, schemaTasks...)
+
+		zap.L().Info("split mysql database filter tables task success",
+			zap.String("startTime", fTime.String()),
+			zap.String("schema", pair.OracleSchema),
+			zap.String("mysql schema", pair.MySQLSchema),
+			zap.Int("all tables", len(metaTables)),
+			zap.Int("schema tables", len(schemaTasks)),
+			zap.String("cost", time.Now().Sub(fTime).String()))
 	}
+	return tasks, nil
+}
 
-	zap.L().Info("split mysql database filter tables task success",
-		zap.String("startTime", fTime.String()),
-		zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)),
-		zap.Int("all tables", len(metaTables)),
-		zap.Int("schema tables", len(tasks)),
-		zap.String("cost", time.Now().Sub(fTime).String()))
+// reportTasks 获取 init 阶段记录且运行编号存在 chunk 的表
+func reportTasks(ctx context.Context, dbM *database.Meta, cfg *config.Config, runID uint) ([]database.Wait, error) {
+	status, err := database.NewFullModel(dbM).StatusFullSyncMeta(ctx, &database.Full{RunID: runID})
+	if err != nil {
+		return nil, err
+	}
+	chunkTables := make(map[database.Wait]struct{})
+	for _, s := range status {
+		chunkTables[database.Wait{SchemaNameS: s.SchemaNameS, SchemaNameT: s.SchemaNameT, TableNameT: s.TableNameT}] = struct{}{}
+	}
 
-	if !cfg.AppConfig.SkipSplit {
-		err = metaDB.DB(ctx).Exec(
-			fmt.Sprintf("DELETE FROM `%s`.`full` WHERE schema_name_t = '%s'", cfg.MetaConfig.MetaSchema, strings.ToUpper(cfg.OracleConfig.Schema))).Error
+	var tasks []database.Wait
+	for _, pair := range cfg.SchemaPairs() {
+		metaTables, err := runWaitTables(ctx, dbM, runID, pair)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		err := metaDB.DB(ctx).Exec(fmt.Sprintf("DELETE FROM `%s`.`scan` WHERE schema_name_t = '%s'", cfg.MetaConfig.MetaSchema, strings.ToUpper(cfg.OracleConfig.Schema))).Error
-		if err != nil {
-			return err
+		for _, t := range metaTables {
+			key := database.Wait{SchemaNameS: t.SchemaNameS, SchemaNameT: t.SchemaNameT, TableNameT: t.TableNameT}
+			if _, ok := chunkTables[key]; ok && cfg.FilterConfig.MatchTable(t.TableNameT) {
+				tasks = append(tasks, t)
+			}
 		}
-		zap.L().Warn("delete meta database table finished", zap.String("schema", cfg.MetaConfig.MetaSchema), zap.String("tables", "full,scan"), zap.String("status", "success"), zap.Bool("skip-split", cfg.AppConfig.SkipSplit))
+	}
+	zap.L().Info("report mysql database filter tables task success", zap.Uint("run", runID), zap.Int("chunk tables", len(chunkTables)), zap.Int("report tables", len(tasks)))
+	return tasks, nil
+}
 
-		err = Split(ctx, metaDB, oracleDB, cfg, tasks)
-		if err != nil {
-			return err
-		}
+// runWaitTables 获取 schema 映射运行编号待扫描表，运行编号无记录时复制最近一次 init 阶段记录作为运行编号快照
+func runWaitTables(ctx context.Context, dbM *database.Meta, runID uint, pair config.SchemaPair) ([]database.Wait, error) {
+	waitTables, err := database.NewWaitModel(dbM).DetailWaitSyncMeta(ctx, &database.Wait{
+		RunID:       runID,
+		SchemaNameS: pair.OracleSchema,
+		SchemaNameT: pair.MySQLSchema,
+	})
+	if err != nil || len(waitTables) > 0 {
+		return waitTables, err
 	}
 
-	err = Scan(ctx, metaDB, oracleDB, cfg, tasks)
+	latestRunID, ok, err := database.NewWaitModel(dbM).DetailLatestWaitRunID(ctx, &database.Wait{
+		SchemaNameS: pair.OracleSchema,
+		SchemaNameT: pair.MySQLSchema,
+	})
+	if err != nil || !ok {
+		return nil, err
+	}
+	latestTables, err := database.NewWaitModel(dbM).DetailWaitSyncMeta(ctx, &database.Wait{
+		RunID:       latestRunID,
+		SchemaNameS: pair.OracleSchema,
+		SchemaNameT: pair.MySQLSchema,
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = Statistics(ctx, metaDB, mysqldb, cfg, tasks)
+	err = dbM.Transaction(ctx, func(txnCtx context.Context) error {
+		for _, t := range latestTables {
+			t.ID, t.RunID = 0, runID
+			if err := database.NewWaitModel(dbM).CreateWaitSyncMeta(txnCtx, &t); err != nil {
+				return err
+			}
+			waitTables = append(waitTables, t)
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
+	}
+	zap.L().Info("snapshot meta database wait tables success", zap.String("schema", pair.OracleSchema), zap.String("mysql schema", pair.MySQLSchema), zap.Uint("from run", latestRunID), zap.Uint("run", runID), zap.Int("tables", len(waitTables)))
+	return waitTables, nil
+}
+
+// matchOracleTable 映射后的表名匹配 oracle 数据字典表名，大小写一致优先，否则忽略大小写唯一匹配，返回数据字典表名
+func matchOracleTable(oraTables []string, tableName string) (string, bool) {
+	matched := matchOracleName(oraTables, tableName)
+	if len(matched) == 1 {
+		return matched[0], true
 	}
-	zap.L().Info("scan database program finished", zap.String("cost", time.Now().Sub(sTime).String()))
+	return "", false
+}
 
+// matchOracleName 映射后的名称匹配 oracle 数据字典名称，大小写一致直接返回，否则返回忽略大小写匹配的全部数据字典名称
+// oracle 双引号标识符区分大小写，仅唯一匹配可用于查询
+func matchOracleName(oraNames []string, name string) []string {
+	var matched []string
+	for _, ora := range oraNames {
+		if ora == name {
+			return []string{ora}
+		}
+		if strings.EqualFold(ora, name) {
+			matched = append(matched, ora)
+		}
+	}
+	return matched
+}
+
+// deleteMetaTables 清理指定运行编号元数据表记录
+func deleteMetaTables(ctx context.Context, dbM *database.Meta, cfg *config.Config, runID uint, tables ...string) error {
+	for _, t := range tables {
+		err := dbM.DB(ctx).Table(common.QuoteMySQLTable(cfg.MetaConfig.MetaSchema, t)).Where("run_id = ?", runID).Delete(map[string]interface{}{}).Error
+		if err != nil {
+			return err
+		}
+	}
+	zap.L().Warn("delete meta database table finished", zap.String("schema", cfg.MetaConfig.MetaSchema), zap.Uint("run", runID), zap.String("tables", strings.Join(tables, ",")), zap.String("status", "success"))
 	return nil
 }
 
-func Init(ctx context.Context, dbM *database.Meta, dbS *database.MySQL, cfg *config.Config) error {
+func Init(ctx context.Context, dbM *database.Meta, dbS *database.MySQL, cfg *config.Config, runID uint) error {
 	sTime := time.Now()
-	err := dbM.MigrateTables()
-	if err != nil {
-		return err
+
+	type initTable struct {
+		pair      config.SchemaPair
+		tableName string
 	}
-	zap.L().Info("migrate meta tables success", zap.String("cost", time.Now().Sub(sTime).String()))
+	var tables []initTable
+	for _, pair := range cfg.SchemaPairs() {
+		// 同一运行编号重新 init 清理该运行编号待扫描表记录，历史运行编号记录保留
+		err := database.NewWaitModel(dbM).DeleteWaitSyncMeta(ctx, &database.Wait{
+			RunID:       runID,
+			SchemaNameS: pair.OracleSchema,
+			SchemaNameT: pair.MySQLSchema,
+		})
+		if err != nil {
+			return err
+		}
 
-	tTime := time.Now()
-	tables, err := dbS.GetMySQLTables(cfg.MySQLConfig.Schema)
-	if err != nil {
-		return err
+		tTime := time.Now()
+		mysqlTables, err := dbS.GetMySQLTables(ctx, pair.MySQLSchema)
+		if err != nil {
+			return err
+		}
+
+		var matchTables int
+		for _, t := range mysqlTables {
+			if cfg.FilterConfig.MatchTable(t) {
+				tables = append(tables, initTable{pair: pair, tableName: t})
+				matchTables++
+			}
+		}
+		zap.L().Info("get mysql database all tables success", zap.String("schema", pair.MySQLSchema), zap.Int("all tables", len(mysqlTables)), zap.Int("match tables", matchTables), zap.String("cost", time.Now().Sub(tTime).String()))
 	}
-	zap.L().Info("get mysql database all tables success", zap.String("cost", time.Now().Sub(tTime).String()))
 
 	g := workpool.New(cfg.AppConfig.InitThread)
 
 	for _, tab := range tables {
-		t := tab
+		t, pair := tab.tableName, tab.pair
 		g.Do(func() error {
 			mTime := time.Now()
-			zap.L().Info("get mysql database decimal single table starting", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t)), zap.String("startTime", mTime.String()))
+			zap.L().Info("get mysql database decimal single table starting", zap.String("schema", pair.MySQLSchema), zap.String("table", strings.ToUpper(t)), zap.String("startTime", mTime.String()))
 
-			columns, err := dbS.GetMySQLTableColumn(cfg.MySQLConfig.Schema, t)
+			columns, err := dbS.GetMySQLTableColumn(ctx, pair.MySQLSchema, t)
 			if err != nil {
 				return err
 			}
 
-			var column []string
+			checkColumns, err := newCheckColumns(cfg, t, columns)
+			if err != nil {
+				return err
+			}
+
+			var columnS, columnT []string
+			checked := make(map[string]struct{})
+			for _, c := range checkColumns {
+				columnS = append(columnS, c.ColumnNameS)
+				columnT = append(columnT, c.ColumnName)
+				checked[c.ColumnName] = struct{}{}
+			}
 
 			for _, c := range columns {
-				if strings.EqualFold(c["DATA_TYPE"], "DECIMAL") {
-					precision, err := strconv.Atoi(c["DATA_PRECISION"])
-					if err != nil {
-						return err
-					}
-					scale, err := strconv.Atoi(c["DATA_SCALE"])
-					if err != nil {
-						return err
-					}
-					switch scale {
-					case 0:
-						// bigint <= decimal(19,0)
-						if precision >= 19 {
-							column = append(column, c["COLUMN_NAME"])
-						}
-					default:
-						zap.L().Warn("current table decimal single data_scale",
-							zap.String("schema", cfg.MySQLConfig.Schema),
-							zap.String("table", t),
-							zap.String("datatype", fmt.Sprintf("decimal(%d,%d)", precision, scale)))
-					}
+				if _, ok := checked[c["COLUMN_NAME"]]; !ok && cfg.FilterConfig.MatchColumn(t, c["COLUMN_NAME"]) && strings.EqualFold(c["DATA_TYPE"], "DECIMAL") && !strings.EqualFold(c["DATA_SCALE"], "0") {
+					zap.L().Warn("current table decimal single data_scale",
+						zap.String("schema", pair.MySQLSchema),
+						zap.String("table", t),
+						zap.String("datatype", fmt.Sprintf("decimal(%s,%s)", c["DATA_PRECISION"], c["DATA_SCALE"])))
 				}
 			}
 
-			if len(column) > 0 {
-				column = append(column, "ROWID")
+			if len(columnT) > 0 {
 				err = database.NewWaitModel(dbM).CreateWaitSyncMeta(ctx, &database.Wait{
-					SchemaNameT:   strings.ToUpper(cfg.MySQLConfig.Schema),
-					TableNameS:    strings.ToUpper(t),
-					ColumnDetailS: strings.Join(column, ","),
+					RunID:         runID,
+					SchemaNameS:   pair.OracleSchema,
+					SchemaNameT:   pair.MySQLSchema,
+					TableNameS:    cfg.MappingConfig.OracleTableName(t),
+					TableNameT:    strings.ToUpper(t),
+					ColumnDetailS: strings.Join(append(columnS, "ROWID"), ","),
+					ColumnDetailT: strings.Join(columnT, ","),
 				})
 				if err != nil {
 					return err
 				}
 			}
 
-			zap.L().Info("get mysql database decimal single table success", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t)), zap.String("cost", time.Now().Sub(mTime).String()))
+			zap.L().Info("get mysql database decimal single table success", zap.String("schema", pair.MySQLSchema), zap.String("table", strings.ToUpper(t)), zap.String("cost", time.Now().Sub(mTime).String()))
 			return nil
 		})
 	}
@@ -226,7 +452,7 @@ func Init(ctx context.Context, dbM *database.Meta, dbS *database.MySQL, cfg *con
 	return nil
 }
 
-func Split(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *config.Config, tables []database.Wait) error {
+func Split(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *config.Config, runID uint, tables []database.Wait) error {
 	sTime := time.Now()
 	zap.L().Info("split mysql database decimal tables task starting", zap.String("startTime", sTime.String()))
 
@@ -234,23 +460,35 @@ func Split(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *c
 
 	for _, tab := range tables {
 		t := tab
-		g.Do(func() error {
+		g.Do(func() (err error) {
 			mTime := time.Now()
-			zap.L().Info("split mysql database decimal single table starting", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t.TableNameS)), zap.String("startTime", mTime.String()))
+			zap.L().Info("split mysql database decimal single table starting", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("startTime", mTime.String()))
 
 			taskName := uuid.New().String()
 
-			if err := dbT.StartOracleChunkCreateTask(taskName); err != nil {
+			if err = dbT.StartOracleChunkCreateTask(ctx, taskName); err != nil {
 				return err
 			}
+			// chunk 任务完成、失败或者程序中断均删除 oracle 任务，ctx 已取消时使用独立 context
+			defer func() {
+				closeCtx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.AppConfig.CallTimeout)*time.Second)
+				defer cancel()
+				if cErr := dbT.CloseOracleChunkTask(closeCtx, taskName); cErr != nil {
+					if err == nil {
+						err = cErr
+						return
+					}
+					zap.L().Error("split mysql database decimal single table close task failed", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("task", taskName), zap.Error(cErr))
+				}
+			}()
 
 			rTime := time.Now()
-			if err := dbT.StartOracleCreateChunkByRowID(taskName, strings.ToUpper(cfg.OracleConfig.Schema), strings.ToUpper(t.TableNameS), strconv.Itoa(cfg.AppConfig.ChunkSize), cfg.AppConfig.CallTimeout); err != nil {
+			if err = dbT.StartOracleCreateChunkByRowID(ctx, taskName, t.SchemaNameS, t.TableNameS, strconv.Itoa(cfg.AppConfig.ChunkSize), cfg.AppConfig.CallTimeout); err != nil {
 				return err
 			}
-			zap.L().Info("split mysql database decimal single table chunk", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t.TableNameS)), zap.String("startTime", rTime.String()), zap.String("cost", time.Now().Sub(rTime).String()))
+			zap.L().Info("split mysql database decimal single table chunk", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("startTime", rTime.String()), zap.String("cost", time.Now().Sub(rTime).String()))
 
-			chunkRes, err := dbT.GetOracleTableChunksByRowID(taskName, cfg.AppConfig.CallTimeout)
+			chunkRes, err := dbT.GetOracleTableChunksByRowID(ctx, taskName, cfg.AppConfig.CallTimeout)
 			if err != nil {
 				return err
 			}
@@ -259,10 +497,13 @@ func Split(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *c
 				var fs []database.Full
 
 				fs = append(fs, database.Full{
-					SchemaNameT:   strings.ToUpper(cfg.OracleConfig.Schema),
-					TableNameT:    strings.ToUpper(t.TableNameS),
+					RunID:         runID,
+					SchemaNameS:   t.SchemaNameS,
+					SchemaNameT:   t.SchemaNameT,
+					TableNameT:    t.TableNameT,
+					TableNameS:    t.TableNameS,
 					SQLHint:       cfg.AppConfig.SQLHint,
-					ColumnDetailT: strings.ToUpper(t.ColumnDetailS),
+					ColumnDetailT: t.ColumnDetailS,
 					ChunkDetailT:  `1 = 1`,
 					TaskStatus:    "WAITING",
 				})
@@ -273,15 +514,18 @@ func Split(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *c
 				return nil
 			}
 
-			zap.L().Info("split mysql database decimal single table chunk", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t.TableNameS)), zap.Int("chunks", len(chunkRes)))
+			zap.L().Info("split mysql database decimal single table chunk", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.Int("chunks", len(chunkRes)))
 
 			var fs []database.Full
 			for _, res := range chunkRes {
 				fs = append(fs, database.Full{
-					SchemaNameT:   strings.ToUpper(cfg.OracleConfig.Schema),
-					TableNameT:    strings.ToUpper(t.TableNameS),
+					RunID:         runID,
+					SchemaNameS:   t.SchemaNameS,
+					SchemaNameT:   t.SchemaNameT,
+					TableNameT:    t.TableNameT,
+					TableNameS:    t.TableNameS,
 					SQLHint:       cfg.AppConfig.SQLHint,
-					ColumnDetailT: strings.ToUpper(t.ColumnDetailS),
+					ColumnDetailT: t.ColumnDetailS,
 					ChunkDetailT:  common.StringsBuilder(res["CMD"]),
 					TaskStatus:    "WAITING",
 				})
@@ -292,11 +536,7 @@ func Split(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *c
 				return err
 			}
 
-			if err = dbT.CloseOracleChunkTask(taskName); err != nil {
-				return err
-			}
-
-			zap.L().Info("split mysql database decimal single table success", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t.TableNameS)), zap.String("cost", time.Now().Sub(mTime).String()))
+			zap.L().Info("split mysql database decimal single table success", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("cost", time.Now().Sub(mTime).String()))
 			return nil
 		})
 	}
@@ -310,32 +550,24 @@ func Split(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *c
 	return nil
 }
 
-func Scan(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *config.Config, tables []database.Wait) error {
+func Scan(ctx context.Context, dbM *database.Meta, dbS *database.MySQL, dbT *database.Oracle, cfg *config.Config, runID uint, tables []database.Wait) error {
 	sTime := time.Now()
 	zap.L().Info("scan oracle database schema tables task starting", zap.String("startTime", sTime.String()))
 
-	bigintStr, err := decimal.NewFromString("9223372036854775807")
-	if err != nil {
-		return err
-	}
-
-	unsinBigintStr, err := decimal.NewFromString("18446744073709551615")
-	if err != nil {
-		return err
-	}
-
 	g0 := workpool.New(cfg.AppConfig.TableThread)
 
 	for _, tab := range tables {
 		t := tab
 		g0.Do(func() error {
 			mTime := time.Now()
-			zap.L().Info("scan oracle database decimal single table starting", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t.TableNameS)), zap.String("starttime", mTime.String()))
+			zap.L().Info("scan oracle database decimal single table starting", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("starttime", mTime.String()))
 
 			var metas []database.Full
 			waitMetas, err := database.NewFullModel(dbM).DetailFullSyncMeta(ctx, &database.Full{
-				SchemaNameT: strings.ToUpper(cfg.OracleConfig.Schema),
-				TableNameT:  t.TableNameS,
+				RunID:       runID,
+				SchemaNameS: t.SchemaNameS,
+				SchemaNameT: t.SchemaNameT,
+				TableNameT:  t.TableNameT,
 				TaskStatus:  "WAITING",
 			})
 			if err != nil {
@@ -343,8 +575,10 @@ func Scan(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *co
 			}
 
 			failedMetas, err := database.NewFullModel(dbM).DetailFullSyncMeta(ctx, &database.Full{
-				SchemaNameT: strings.ToUpper(cfg.OracleConfig.Schema),
-				TableNameT:  t.TableNameS,
+				RunID:       runID,
+				SchemaNameS: t.SchemaNameS,
+				SchemaNameT: t.SchemaNameT,
+				TableNameT:  t.TableNameT,
 				TaskStatus:  "FAILED",
 			})
 			if err != nil {
@@ -352,8 +586,10 @@ func Scan(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *co
 			}
 
 			runMetas, err := database.NewFullModel(dbM).DetailFullSyncMeta(ctx, &database.Full{
-				SchemaNameT: strings.ToUpper(cfg.OracleConfig.Schema),
-				TableNameT:  t.TableNameS,
+				RunID:       runID,
+				SchemaNameS: t.SchemaNameS,
+				SchemaNameT: t.SchemaNameT,
+				TableNameT:  t.TableNameT,
 				TaskStatus:  "RUNNING",
 			})
 			if err != nil {
@@ -364,15 +600,118 @@ func Scan(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *co
 			metas = append(metas, failedMetas...)
 			metas = append(metas, runMetas...)
 
+			checkColumns, _, err := getCheckColumns(ctx, dbS, cfg, t)
+			if err != nil {
+				return err
+			}
+			checkColumns, err = resolveOracleColumns(ctx, dbT, t, checkColumns)
+			if err != nil {
+				return err
+			}
+
+			// 断点续扫，仅已完成且本次不再扫描的 chunk 结果用于判定字段是否已无需继续扫描以及扣减 rowid 样本数，重新扫描 chunk 的历史结果将被替换
+			successMetas, err := database.NewFullModel(dbM).DetailFullSyncMeta(ctx, &database.Full{
+				RunID:       runID,
+				SchemaNameS: t.SchemaNameS,
+				SchemaNameT: t.SchemaNameT,
+				TableNameT:  t.TableNameT,
+				TaskStatus:  "SUCCESS",
+			})
+			if err != nil {
+				return err
+			}
+			successChunks := make(map[string]struct{})
+			for _, m := range successMetas {
+				successChunks[m.ChunkDetailT] = struct{}{}
+			}
+			for _, m := range metas {
+				delete(successChunks, m.ChunkDetailT)
+			}
+
+			summaries, err := database.NewSummaryModel(dbM).DetailSummaryResult(ctx, &database.Summary{
+				RunID:       runID,
+				SchemaNameS: t.SchemaNameS,
+				SchemaNameT: t.SchemaNameT,
+				TableNameT:  t.TableNameT,
+			})
+			if err != nil {
+				return err
+			}
+			var successSummaries []database.Summary
+			for _, s := range summaries {
+				if _, ok := successChunks[s.ChunkDetailT]; ok {
+					successSummaries = append(successSummaries, s)
+				}
+			}
+			tableState := newColumnState()
+			if err = tableState.merge(successSummaries); err != nil {
+				return err
+			}
+
+			results, err := database.NewScanModel(dbM).DetailScanResult(ctx, &database.Scan{
+				RunID:       runID,
+				SchemaNameS: t.SchemaNameS,
+				SchemaNameT: t.SchemaNameT,
+				TableNameT:  t.TableNameT,
+			})
+			if err != nil {
+				return err
+			}
+			var successResults []database.Scan
+			for _, r := range results {
+				if _, ok := successChunks[r.ChunkDetailT]; ok {
+					successResults = append(successResults, r)
+				}
+			}
+			tableSample := newColumnSample(cfg.AppConfig.MaxSampleRows)
+			tableSample.take(successResults)
+
 			g := workpool.New(cfg.AppConfig.SQLThread)
 
 			for _, mt := range metas {
 				m := mt
-				g.Do(func() error {
+				g.Do(func() (err error) {
+					// 程序中断不再处理排队 chunk
+					if err = ctx.Err(); err != nil {
+						return err
+					}
+
 					tTime := time.Now()
-					zap.L().Info("scan oracle database decimal single table chunk starting", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t.TableNameS)), zap.String("column", m.ColumnDetailT), zap.String("chunk", m.ChunkDetailT), zap.String("startTime", tTime.String()))
+
+					var (
+						scanResults []database.Scan
+						summaries   []database.Summary
+					)
+
+					// 剔除已判定的字段，字段全部判定则跳过剩余 chunk
+					scanColumns := checkColumns
+					if !cfg.AppConfig.ExactCount {
+						scanColumns = tableState.filter(checkColumns)
+					}
+					if len(scanColumns) == 0 {
+						zap.L().Warn("scan oracle database decimal single table chunk skip", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("chunk", m.ChunkDetailT), zap.String("reason", "all columns decided"))
+						return database.NewFullModel(dbM).UpdateFullSyncMetaChunk(ctx, &database.Full{
+							RunID:        m.RunID,
+							SchemaNameS:  m.SchemaNameS,
+							SchemaNameT:  m.SchemaNameT,
+							TableNameT:   m.TableNameT,
+							ChunkDetailT: m.ChunkDetailT,
+						}, map[string]interface{}{
+							"TaskStatus": "SKIPPED",
+						})
+					}
+
+					var columnNames []string
+					for _, c := range scanColumns {
+						columnNames = append(columnNames, c.ColumnNameS)
+					}
+					m.ColumnDetailT = strings.Join(append(columnNames, "ROWID"), ",")
+
+					zap.L().Info("scan oracle database decimal single table chunk starting", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("column", m.ColumnDetailT), zap.String("chunk", m.ChunkDetailT), zap.String("startTime", tTime.String()))
 
 					err = database.NewFullModel(dbM).UpdateFullSyncMetaChunk(ctx, &database.Full{
+						RunID:        m.RunID,
+						SchemaNameS:  m.SchemaNameS,
 						SchemaNameT:  m.SchemaNameT,
 						TableNameT:   m.TableNameT,
 						ChunkDetailT: m.ChunkDetailT,
@@ -382,31 +721,141 @@ func Scan(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *co
 					if err != nil {
 						return err
 					}
+					// chunk 扫描失败标记 FAILED，程序中断重置为 WAITING，ctx 已取消时使用独立 context
+					defer func() {
+						if err == nil {
+							return
+						}
+						status := "FAILED"
+						if ctx.Err() != nil {
+							status = "WAITING"
+						}
+						uErr := database.NewFullModel(dbM).UpdateFullSyncMetaChunk(context.Background(), &database.Full{
+							RunID:        m.RunID,
+							SchemaNameS:  m.SchemaNameS,
+							SchemaNameT:  m.SchemaNameT,
+							TableNameT:   m.TableNameT,
+							ChunkDetailT: m.ChunkDetailT,
+						}, map[string]interface{}{
+							"TaskStatus": status,
+						})
+						if uErr != nil {
+							zap.L().Error("scan oracle database decimal single table chunk reset failed", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("chunk", m.ChunkDetailT), zap.String("status", status), zap.Error(uErr))
+						}
+					}()
+
+					sourceDBCharset := common.MigrateOracleCharsetStringConvertMapping[strings.ToUpper(cfg.OracleConfig.Charset)]
+					targetDBCharset := common.MigrateMYSQLCompatibleCharsetStringConvertMapping[strings.ToUpper(cfg.MySQLConfig.Charset)]
+
+					// aggregate 模式仅下推只有 INTEGER 校验类型的字段，其余字段逐行扫描
+					var aggrColumns, rowColumns []database.CheckColumn
+					switch strings.ToUpper(cfg.AppConfig.ScanMode) {
+					case "AGGREGATE":
+						for _, c := range scanColumns {
+							if c.OnlyCheck(database.CheckTypeInteger) {
+								aggrColumns = append(aggrColumns, c)
+							} else {
+								rowColumns = append(rowColumns, c)
+							}
+						}
+					default:
+						rowColumns = scanColumns
+					}
 
-					scanResults, err := dbT.ScanOracleTableDecimalData(m, common.MigrateOracleCharsetStringConvertMapping[strings.ToUpper(cfg.OracleConfig.Charset)], common.MigrateMYSQLCompatibleCharsetStringConvertMapping[strings.ToUpper(cfg.MySQLConfig.Charset)], bigintStr, unsinBigintStr, cfg.AppConfig.CallTimeout)
-					if err != nil {
-						return err
+					if len(aggrColumns) > 0 {
+						aggrSummaries, err := dbT.ScanOracleTableDecimalAggregate(ctx, m, aggrColumns, sourceDBCharset, targetDBCharset, cfg.AppConfig.CallTimeout)
+						if err != nil {
+							return err
+						}
+
+						// chunk 极值超出 BIGINT 取值范围的字段，回退逐行扫描获取越界数据 rowid
+						for i, r := range aggrSummaries {
+							if r.NotNullCount > 0 {
+								minValue, err := decimal.NewFromString(r.MinValue)
+								if err != nil {
+									return err
+								}
+								maxValue, err := decimal.NewFromString(r.MaxValue)
+								if err != nil {
+									return err
+								}
+								if minValue.Cmp(common.BigintMin) == -1 || maxValue.Cmp(common.BigintMax) == 1 {
+									zap.L().Warn("scan oracle database decimal single table chunk fallback row mode", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("column", r.ColumnName), zap.String("chunk", m.ChunkDetailT))
+									rowColumns = append(rowColumns, aggrColumns[i])
+									continue
+								}
+							}
+							summaries = append(summaries, r)
+						}
 					}
 
-					if len(scanResults) > 0 {
-						err = database.NewScanModel(dbM).BatchCreateScanResult(ctx, scanResults, cfg.AppConfig.BatchSize)
+					if len(rowColumns) > 0 {
+						rowResults, rowSummaries, err := dbT.ScanOracleTableData(ctx, m, rowColumns, sourceDBCharset, targetDBCharset, cfg.AppConfig.CallTimeout)
 						if err != nil {
 							return err
 						}
+						scanResults = append(scanResults, rowResults...)
+						summaries = append(summaries, rowSummaries...)
 					}
 
-					err = database.NewFullModel(dbM).UpdateFullSyncMetaChunk(ctx, &database.Full{
-						SchemaNameT:  m.SchemaNameT,
-						TableNameT:   m.TableNameT,
-						ChunkDetailT: m.ChunkDetailT,
-					}, map[string]interface{}{
-						"TaskStatus": "SUCCESS",
+					// 越界数据总行数记录于 summary，rowid 样本按字段限制记录数
+					scanResults = tableSample.take(scanResults)
+
+					// chunk 扫描结果替换历史扫描结果，与 SUCCESS 状态同一事务提交，断点续扫重复处理 chunk 不产生重复记录
+					err = dbM.Transaction(ctx, func(txnCtx context.Context) error {
+						err := database.NewScanModel(dbM).DeleteScanResult(txnCtx, &database.Scan{
+							RunID:        m.RunID,
+							SchemaNameS:  m.SchemaNameS,
+							SchemaNameT:  m.SchemaNameT,
+							TableNameT:   m.TableNameT,
+							ChunkDetailT: m.ChunkDetailT,
+						})
+						if err != nil {
+							return err
+						}
+						err = database.NewSummaryModel(dbM).DeleteSummaryResult(txnCtx, &database.Summary{
+							RunID:        m.RunID,
+							SchemaNameS:  m.SchemaNameS,
+							SchemaNameT:  m.SchemaNameT,
+							TableNameT:   m.TableNameT,
+							ChunkDetailT: m.ChunkDetailT,
+						})
+						if err != nil {
+							return err
+						}
+
+						if len(scanResults) > 0 {
+							err = database.NewScanModel(dbM).BatchCreateScanResult(txnCtx, scanResults, cfg.AppConfig.BatchSize)
+							if err != nil {
+								return err
+							}
+						}
+						if len(summaries) > 0 {
+							err = database.NewSummaryModel(dbM).BatchCreateSummaryResult(txnCtx, summaries, cfg.AppConfig.BatchSize)
+							if err != nil {
+								return err
+							}
+						}
+
+						return database.NewFullModel(dbM).UpdateFullSyncMetaChunk(txnCtx, &database.Full{
+							RunID:        m.RunID,
+							SchemaNameS:  m.SchemaNameS,
+							SchemaNameT:  m.SchemaNameT,
+							TableNameT:   m.TableNameT,
+							ChunkDetailT: m.ChunkDetailT,
+						}, map[string]interface{}{
+							"TaskStatus": "SUCCESS",
+						})
 					})
 					if err != nil {
 						return err
 					}
 
-					zap.L().Info("scan oracle database decimal single table chunk success", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t.TableNameS)), zap.String("column", m.ColumnDetailT), zap.String("chunk", m.ChunkDetailT), zap.String("cost", time.Now().Sub(tTime).String()))
+					if err = tableState.merge(summaries); err != nil {
+						return err
+					}
+
+					zap.L().Info("scan oracle database decimal single table chunk success", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("column", m.ColumnDetailT), zap.String("chunk", m.ChunkDetailT), zap.String("cost", time.Now().Sub(tTime).String()))
 
 					return nil
 				})
@@ -416,7 +865,7 @@ func Scan(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *co
 				return err
 			}
 
-			zap.L().Info("scan oracle database decimal single tables success", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t.TableNameS)), zap.String("cost", time.Now().Sub(mTime).String()))
+			zap.L().Info("scan oracle database decimal single tables success", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("cost", time.Now().Sub(mTime).String()))
 			return nil
 		})
 	}
@@ -425,113 +874,123 @@ func Scan(ctx context.Context, dbM *database.Meta, dbT *database.Oracle, cfg *co
 		return err
 	}
 
-	zap.L().Info("scan oracle database schema tables task success", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("cost", time.Now().Sub(sTime).String()))
+	zap.L().Info("scan oracle database schema tables task success", zap.String("cost", time.Now().Sub(sTime).String()))
 
 	return nil
 }
 
-func Statistics(ctx context.Context, dbM *database.Meta, dbS *database.MySQL, cfg *config.Config, tables []database.Wait) error {
+func Statistics(ctx context.Context, dbM *database.Meta, dbS *database.MySQL, cfg *config.Config, runID uint, tables []database.Wait) error {
 	sTime := time.Now()
 	zap.L().Info("statistics mysql database decimal tables task starting", zap.String("startTime", sTime.String()))
 
+	combined, err := alterTableCombined(ctx, dbS, cfg)
+	if err != nil {
+		return err
+	}
+
 	g := workpool.New(cfg.AppConfig.InitThread)
 
 	for _, tab := range tables {
 		t := tab
 		g.Do(func() error {
 			mTime := time.Now()
-			zap.L().Info("statistics mysql database decimal single table starting", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t.TableNameS)), zap.String("startTime", mTime.String()))
+			zap.L().Info("statistics mysql database decimal single table starting", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("startTime", mTime.String()))
 
-			originColumns := strings.Split(t.ColumnDetailS, ",")
+			checkColumns, columns, err := getCheckColumns(ctx, dbS, cfg, t)
+			if err != nil {
+				return err
+			}
+
+			summaries, err := database.NewSummaryModel(dbM).DetailSummaryResult(ctx, &database.Summary{
+				RunID:       runID,
+				SchemaNameS: t.SchemaNameS,
+				SchemaNameT: t.SchemaNameT,
+				TableNameT:  t.TableNameT,
+			})
+			if err != nil {
+				return err
+			}
 
-			columns, err := dbS.GetMySQLTableColumn(cfg.MySQLConfig.Schema, t.TableNameS)
+			// 表存在 chunk 且 chunk 均扫描完成，否则字段无完整扫描依据，不生成建议
+			chunks, finished, err := tableChunkCount(ctx, dbM, runID, t)
 			if err != nil {
 				return err
 			}
+			completed := chunks > 0 && chunks == finished
+			if !completed {
+				zap.L().Warn("statistics mysql database decimal single table chunk unfinished", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.Int64("chunks", chunks), zap.Int64("finished", finished))
+			}
+
+			// 汇总各 chunk 字段校验统计
+			tableState := newColumnState()
+			if err = tableState.merge(summaries); err != nil {
+				return err
+			}
 
 			results, err := database.NewScanModel(dbM).DetailScanResult(ctx, &database.Scan{
-				SchemaNameT: strings.ToUpper(cfg.OracleConfig.Schema),
-				TableNameT:  strings.ToUpper(t.TableNameS),
+				RunID:       runID,
+				SchemaNameS: t.SchemaNameS,
+				SchemaNameT: t.SchemaNameT,
+				TableNameT:  t.TableNameT,
 			})
 			if err != nil {
 				return err
 			}
 
-			resl := make(map[string]struct{})
-			for _, r := range results {
-				resl[r.ColumnName] = struct{}{}
+			violations := tableState.violations(results, chunks)
+			if len(violations) > 0 {
+				err = database.NewViolationModel(dbM).BatchCreateViolation(ctx, violations, cfg.AppConfig.BatchSize)
+				if err != nil {
+					return err
+				}
 			}
 
-			if len(results) == 0 {
-				err = database.NewStatisticsModel(dbM).CreateStatistics(ctx, &database.Statistics{
-					SchemaNameT:     strings.ToUpper(cfg.OracleConfig.Schema),
-					TableNameT:      strings.ToUpper(t.TableNameS),
-					ModifyColumn:    "",
-					NotModifyColumn: "",
-				})
+			profiles := tableState.profiles(runID, t.SchemaNameS, t.SchemaNameT, t.TableNameT)
+			if len(profiles) > 0 {
+				err = database.NewProfileModel(dbM).BatchCreateProfile(ctx, profiles, cfg.AppConfig.BatchSize)
 				if err != nil {
 					return err
 				}
-			} else {
-				var (
-					canModify   []string
-					canotModify []string
-				)
-				for _, c := range originColumns {
-					if _, ok := resl[c]; ok {
-						canotModify = append(canotModify, c)
-					} else {
-						for _, col := range columns {
-							if strings.EqualFold(col["COLUMN_NAME"], c) {
-								var sqlStr string
-
-								switch {
-								case strings.EqualFold(col["NULLABLE"], "N"):
-									if strings.EqualFold(col["DATA_DEFAULT"], "NULLSTRING") {
-										if strings.EqualFold(col["COMMENTS"], "") {
-											sqlStr = fmt.Sprintf("ALTER TABLE `%s`.`%s` MODIFY `%s` BIGINT(20) NOT NULL", strings.ToUpper(cfg.MySQLConfig.Schema), t.TableNameS, c)
-										} else {
-											sqlStr = fmt.Sprintf("ALTER TABLE `%s`.`%s` MODIFY `%s` BIGINT(20) NOT NULL COMMENT '%s'", strings.ToUpper(cfg.MySQLConfig.Schema), t.TableNameS, c, col["COMMENTS"])
-										}
-									} else {
-										if strings.EqualFold(col["COMMENTS"], "") {
-											sqlStr = fmt.Sprintf("ALTER TABLE `%s`.`%s` MODIFY `%s` BIGINT(20) NOT NULL DEFAULT %s", strings.ToUpper(cfg.MySQLConfig.Schema), t.TableNameS, c, col["DATA_DEFAULT"])
-										} else {
-											sqlStr = fmt.Sprintf("ALTER TABLE `%s`.`%s` MODIFY `%s` BIGINT(20) NOT NULL DEFAULT %s COMMENT '%s'", strings.ToUpper(cfg.MySQLConfig.Schema), t.TableNameS, c, col["DATA_DEFAULT"], col["COMMENTS"])
-										}
-									}
-								default:
-									if strings.EqualFold(col["DATA_DEFAULT"], "NULLSTRING") {
-										if strings.EqualFold(col["COMMENTS"], "") {
-											sqlStr = fmt.Sprintf("ALTER TABLE `%s`.`%s` MODIFY `%s` BIGINT(20) DEFAULT NULL", strings.ToUpper(cfg.MySQLConfig.Schema), t.TableNameS, c)
-										} else {
-											sqlStr = fmt.Sprintf("ALTER TABLE `%s`.`%s` MODIFY `%s` BIGINT(20) DEFAULT NULL COMMENT '%s'", strings.ToUpper(cfg.MySQLConfig.Schema), t.TableNameS, c, col["COMMENTS"])
-										}
-									} else {
-										if strings.EqualFold(col["COMMENTS"], "") {
-											sqlStr = fmt.Sprintf("ALTER TABLE `%s`.`%s` MODIFY `%s` BIGINT(20) DEFAULT %s", strings.ToUpper(cfg.MySQLConfig.Schema), t.TableNameS, c, col["DATA_DEFAULT"])
-										} else {
-											sqlStr = fmt.Sprintf("ALTER TABLE `%s`.`%s` MODIFY `%s` BIGINT(20) DEFAULT %s COMMENT '%s'", strings.ToUpper(cfg.MySQLConfig.Schema), t.TableNameS, c, col["DATA_DEFAULT"], col["COMMENTS"])
-										}
-									}
-								}
+			}
 
-								canModify = append(canModify, sqlStr)
-							}
+			var (
+				canModify   []string
+				canotModify []string
+				rollbacks   []string
+				unevaluated []string
+			)
+			for _, c := range checkColumns {
+				for _, col := range columns {
+					if strings.EqualFold(col["COLUMN_NAME"], c.ColumnName) {
+						if !completed || !tableState.evaluated(c) {
+							unevaluated = append(unevaluated, c.ColumnName)
+							continue
+						}
+						columnType, ok := tableState.recommend(c)
+						switch {
+						case !ok:
+							canotModify = append(canotModify, c.ColumnName)
+						case !strings.EqualFold(columnType, ""):
+							canModify = append(canModify, genModifyColumnClause(col, columnType))
+							rollbacks = append([]string{genModifyColumnClause(col, originColumnType(col))}, rollbacks...)
 						}
 					}
 				}
-				err = database.NewStatisticsModel(dbM).CreateStatistics(ctx, &database.Statistics{
-					SchemaNameT:     strings.ToUpper(cfg.OracleConfig.Schema),
-					TableNameT:      strings.ToUpper(t.TableNameS),
-					ModifyColumn:    strings.Join(canModify, ";\n"),
-					NotModifyColumn: strings.Join(canotModify, ","),
-				})
-				if err != nil {
-					return err
-				}
 			}
-			zap.L().Info("statistics mysql database decimal single table success", zap.String("schema", strings.ToUpper(cfg.OracleConfig.Schema)), zap.String("table", strings.ToUpper(t.TableNameS)), zap.String("cost", time.Now().Sub(mTime).String()))
+			err = database.NewStatisticsModel(dbM).CreateStatistics(ctx, &database.Statistics{
+				RunID:             runID,
+				SchemaNameS:       t.SchemaNameS,
+				SchemaNameT:       t.SchemaNameT,
+				TableNameT:        t.TableNameT,
+				ModifyColumn:      genAlterTableSQL(columns, canModify, combined),
+				NotModifyColumn:   strings.Join(canotModify, ","),
+				RollbackColumn:    genAlterTableSQL(columns, rollbacks, combined),
+				UnevaluatedColumn: strings.Join(unevaluated, ","),
+			})
+			if err != nil {
+				return err
+			}
+			zap.L().Info("statistics mysql database decimal single table success", zap.String("schema", t.SchemaNameS), zap.String("table", t.TableNameT), zap.String("cost", time.Now().Sub(mTime).String()))
 			return nil
 		})
 	}
@@ -543,3 +1002,177 @@ func Statistics(ctx context.Context, dbM *database.Meta, dbS *database.MySQL, cf
 	zap.L().Info("statistics mysql database decimal tables task success", zap.String("cost", time.Now().Sub(sTime).String()))
 	return nil
 }
+
+// tableChunkCount 获取运行编号表 chunk 总数以及扫描完成 (SUCCESS、SKIPPED) chunk 数
+func tableChunkCount(ctx context.Context, dbM *database.Meta, runID uint, t database.Wait) (int64, int64, error) {
+	status, err := database.NewFullModel(dbM).StatusFullSyncMeta(ctx, &database.Full{
+		RunID:       runID,
+		SchemaNameS: t.SchemaNameS,
+		SchemaNameT: t.SchemaNameT,
+		TableNameT:  t.TableNameT,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	var chunks, finished int64
+	for _, s := range status {
+		chunks += s.ChunkCount
+		if strings.EqualFold(s.TaskStatus, "SUCCESS") || strings.EqualFold(s.TaskStatus, "SKIPPED") {
+			finished += s.ChunkCount
+		}
+	}
+	return chunks, finished, nil
+}
+
+// alterTableCombined 是否每张表合并为单条多子句 ALTER TABLE
+func alterTableCombined(ctx context.Context, dbS *database.MySQL, cfg *config.Config) (bool, error) {
+	switch strings.ToLower(cfg.AppConfig.AlterMode) {
+	case config.AlterModeTable:
+		return true, nil
+	case config.AlterModeColumn:
+		return false, nil
+	}
+	version, err := dbS.GetMySQLVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+	combined := common.SupportMultiSchemaChange(version)
+	zap.L().Info("statistics mysql database alter mode", zap.String("version", version), zap.Bool("combined", combined))
+	return combined, nil
+}
+
+// genAlterTableSQL 生成表修改字段语句，combined 合并为单条 ALTER TABLE，否则每个子句单独生成 ALTER TABLE，表名取自 information_schema 实际大小写
+func genAlterTableSQL(columns []map[string]string, clauses []string, combined bool) string {
+	if len(clauses) == 0 || len(columns) == 0 {
+		return ""
+	}
+	table := common.QuoteMySQLTable(columns[0]["TABLE_SCHEMA"], columns[0]["TABLE_NAME"])
+	if combined {
+		return fmt.Sprintf("ALTER TABLE %s %s", table, strings.Join(clauses, ", "))
+	}
+	var stmts []string
+	for _, c := range clauses {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s %s", table, c))
+	}
+	return strings.Join(stmts, ";\n")
+}
+
+// originColumnType 原字段类型，unsigned、zerofill 由 genColumnDefinition 按原字段定义追加
+func originColumnType(col map[string]string) string {
+	var fields []string
+	for _, f := range strings.Fields(col["COLUMN_TYPE"]) {
+		if !strings.EqualFold(f, "UNSIGNED") && !strings.EqualFold(f, "ZEROFILL") {
+			fields = append(fields, f)
+		}
+	}
+	return strings.ToUpper(strings.Join(fields, " "))
+}
+
+// genModifyColumnClause 生成字段 MODIFY 子句
+func genModifyColumnClause(col map[string]string, columnType string) string {
+	return "MODIFY " + genColumnDefinition(col, columnType)
+}
+
+// genColumnDefinition 生成字段定义，除字段类型外保留 unsigned、字符集、排序规则、生成列、非空、默认值、on update、可见性、自增以及注释属性
+func genColumnDefinition(col map[string]string, columnType string) string {
+	var (
+		defs       = []string{common.QuoteMySQLIdentifier(col["COLUMN_NAME"]) + " " + columnType}
+		originType = strings.ToUpper(col["COLUMN_TYPE"])
+		extra      = strings.ToUpper(col["EXTRA"])
+		generated  bool
+	)
+
+	switch {
+	case isNumericType(columnType):
+		// 建议字段类型已包含 unsigned、zerofill 时不重复追加
+		if strings.Contains(originType, "UNSIGNED") && !strings.Contains(strings.ToUpper(columnType), "UNSIGNED") {
+			defs = append(defs, "UNSIGNED")
+		}
+		if strings.Contains(originType, "ZEROFILL") && !strings.Contains(strings.ToUpper(columnType), "ZEROFILL") {
+			defs = append(defs, "ZEROFILL")
+		}
+	case isStringType(columnType):
+		if !strings.EqualFold(col["CHARACTER_SET_NAME"], "UNKNOWN") {
+			defs = append(defs, "CHARACTER SET "+col["CHARACTER_SET_NAME"])
+		}
+		if !strings.EqualFold(col["COLLATION_NAME"], "UNKNOWN") {
+			defs = append(defs, "COLLATE "+col["COLLATION_NAME"])
+		}
+	}
+
+	// 生成列不可指定默认值，EXTRA DEFAULT_GENERATED 表示表达式默认值并非生成列
+	switch {
+	case strings.Contains(extra, "VIRTUAL GENERATED"):
+		generated = true
+		defs = append(defs, fmt.Sprintf("GENERATED ALWAYS AS (%s) VIRTUAL", col["GENERATION_EXPRESSION"]))
+	case strings.Contains(extra, "STORED GENERATED"):
+		generated = true
+		defs = append(defs, fmt.Sprintf("GENERATED ALWAYS AS (%s) STORED", col["GENERATION_EXPRESSION"]))
+	}
+
+	notNull := strings.EqualFold(col["NULLABLE"], "N")
+	if notNull {
+		defs = append(defs, "NOT NULL")
+	}
+	if !generated {
+		switch {
+		case !strings.EqualFold(col["DATA_DEFAULT"], "NULLSTRING"):
+			defs = append(defs, "DEFAULT "+common.MySQLDefaultValue(numericDefault(col["DATA_DEFAULT"], col["EXTRA"], columnType), col["EXTRA"]))
+		case !notNull:
+			defs = append(defs, "DEFAULT NULL")
+		}
+		if onUpdate := extraOnUpdate(col["EXTRA"]); onUpdate != "" {
+			defs = append(defs, "ON UPDATE "+onUpdate)
+		}
+	}
+	if strings.Contains(extra, "INVISIBLE") {
+		defs = append(defs, "INVISIBLE")
+	}
+	if strings.Contains(extra, "AUTO_INCREMENT") {
+		defs = append(defs, "AUTO_INCREMENT")
+	}
+	if !strings.EqualFold(col["COMMENTS"], "") {
+		defs = append(defs, "COMMENT "+common.QuoteMySQLString(col["COMMENTS"]))
+	}
+	return strings.Join(defs, " ")
+}
+
+// extraOnUpdate 获取 EXTRA 中 on update 表达式，eg: DEFAULT_GENERATED on update CURRENT_TIMESTAMP(3)
+func extraOnUpdate(extra string) string {
+	fields := strings.Fields(extra)
+	for i := 0; i+2 < len(fields); i++ {
+		if strings.EqualFold(fields[i], "ON") && strings.EqualFold(fields[i+1], "UPDATE") {
+			return fields[i+2]
+		}
+	}
+	return ""
+}
+
+// isNumericType 建议字段类型是否为数值类型
+func isNumericType(columnType string) bool {
+	t := strings.ToUpper(columnType)
+	return strings.Contains(t, "INT") || strings.HasPrefix(t, "DECIMAL") || strings.HasPrefix(t, "DOUBLE") || strings.HasPrefix(t, "FLOAT")
+}
+
+// numericDefault 按建议整型、decimal 小数位重新格式化数值默认值，eg: decimal(20,2) default 0.00 缩小为整型格式化为 0
+func numericDefault(value, extra, columnType string) string {
+	t := strings.ToUpper(columnType)
+	if strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED") || (!strings.Contains(t, "INT") && !strings.HasPrefix(t, "DECIMAL")) {
+		return value
+	}
+	d, err := decimal.NewFromString(value)
+	if err != nil {
+		return value
+	}
+	var precision, scale int
+	if strings.HasPrefix(t, "DECIMAL(") {
+		fmt.Sscanf(t, "DECIMAL(%d,%d)", &precision, &scale)
+	}
+	return d.StringFixed(int32(scale))
+}
+
+// isStringType 建议字段类型是否为字符类型
+func isStringType(columnType string) bool {
+	t := strings.ToUpper(columnType)
+	return strings.Contains(t, "CHAR") || strings.Contains(t, "TEXT")
+}
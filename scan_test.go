@@ -0,0 +1,152 @@
+/*
+Copyright © 2020 Marvin
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenColumnDefinition(t *testing.T) {
+	column := func(overrides map[string]string) map[string]string {
+		col := map[string]string{
+			"TABLE_SCHEMA":          "scans",
+			"TABLE_NAME":            "t",
+			"COLUMN_NAME":           "C",
+			"COLUMN_TYPE":           "decimal(20,0)",
+			"EXTRA":                 "",
+			"GENERATION_EXPRESSION": "",
+			"NULLABLE":              "Y",
+			"DATA_DEFAULT":          "NULLSTRING",
+			"COMMENTS":              "",
+			"CHARACTER_SET_NAME":    "UNKNOWN",
+			"COLLATION_NAME":        "UNKNOWN",
+		}
+		for k, v := range overrides {
+			col[k] = v
+		}
+		return col
+	}
+	cases := []struct {
+		name       string
+		col        map[string]string
+		columnType string
+		want       string
+	}{
+		{
+			name:       "comment with quote",
+			col:        column(map[string]string{"COMMENTS": "it's a \\ comment"}),
+			columnType: "BIGINT",
+			want:       "`C` BIGINT DEFAULT NULL COMMENT 'it''s a \\\\ comment'",
+		},
+		{
+			name:       "name with backtick",
+			col:        column(map[string]string{"COLUMN_NAME": "a`b", "NULLABLE": "N"}),
+			columnType: "BIGINT",
+			want:       "`a``b` BIGINT NOT NULL",
+		},
+		{
+			name:       "string default",
+			col:        column(map[string]string{"NULLABLE": "N", "DATA_DEFAULT": "0"}),
+			columnType: "BIGINT",
+			want:       "`C` BIGINT NOT NULL DEFAULT '0'",
+		},
+		{
+			name:       "timestamp default on update",
+			col:        column(map[string]string{"COLUMN_TYPE": "timestamp(3)", "EXTRA": "DEFAULT_GENERATED on update CURRENT_TIMESTAMP(3)", "DATA_DEFAULT": "CURRENT_TIMESTAMP(3)"}),
+			columnType: "DATETIME(3)",
+			want:       "`C` DATETIME(3) DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3)",
+		},
+		{
+			name:       "unsigned not repeated",
+			col:        column(map[string]string{"COLUMN_TYPE": "decimal(20,0) unsigned", "NULLABLE": "N"}),
+			columnType: "TINYINT(3) UNSIGNED",
+			want:       "`C` TINYINT(3) UNSIGNED NOT NULL",
+		},
+		{
+			name:       "decimal default narrowed to integer",
+			col:        column(map[string]string{"COLUMN_TYPE": "decimal(20,2)", "NULLABLE": "N", "DATA_DEFAULT": "0.00"}),
+			columnType: "TINYINT(4)",
+			want:       "`C` TINYINT(4) NOT NULL DEFAULT '0'",
+		},
+		{
+			name:       "decimal default narrowed scale",
+			col:        column(map[string]string{"COLUMN_TYPE": "decimal(20,4)", "DATA_DEFAULT": "1.5000"}),
+			columnType: "DECIMAL(17,1)",
+			want:       "`C` DECIMAL(17,1) DEFAULT '1.5'",
+		},
+		{
+			name:       "decimal default rollback",
+			col:        column(map[string]string{"COLUMN_TYPE": "decimal(20,2)", "DATA_DEFAULT": "0.00"}),
+			columnType: "DECIMAL(20,2)",
+			want:       "`C` DECIMAL(20,2) DEFAULT '0.00'",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := genColumnDefinition(c.col, c.columnType); got != c.want {
+				t.Errorf("genColumnDefinition() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestMatchOracleTable(t *testing.T) {
+	cases := []struct {
+		name      string
+		oraTables []string
+		table     string
+		want      string
+		wantOK    bool
+	}{
+		{name: "exact", oraTables: []string{"ORDERS", "BILL"}, table: "ORDERS", want: "ORDERS", wantOK: true},
+		{name: "single case insensitive", oraTables: []string{"Orders", "BILL"}, table: "ORDERS", want: "Orders", wantOK: true},
+		{name: "exact wins over case insensitive", oraTables: []string{"orders", "Orders", "ORDERS"}, table: "Orders", want: "Orders", wantOK: true},
+		{name: "exact wins listed last", oraTables: []string{"orders", "ORDERS"}, table: "ORDERS", want: "ORDERS", wantOK: true},
+		{name: "ambiguous case insensitive", oraTables: []string{"orders", "Orders"}, table: "ORDERS", wantOK: false},
+		{name: "missing", oraTables: []string{"BILL"}, table: "ORDERS", wantOK: false},
+		{name: "empty dictionary", table: "ORDERS", wantOK: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := matchOracleTable(c.oraTables, c.table)
+			if got != c.want || ok != c.wantOK {
+				t.Errorf("matchOracleTable(%v, %q) = (%q, %v), want (%q, %v)", c.oraTables, c.table, got, ok, c.want, c.wantOK)
+			}
+		})
+	}
+}
+
+func TestMatchOracleName(t *testing.T) {
+	cases := []struct {
+		name     string
+		oraNames []string
+		in       string
+		want     []string
+	}{
+		{name: "exact", oraNames: []string{"AMOUNT", "amount"}, in: "amount", want: []string{"amount"}},
+		{name: "single case insensitive", oraNames: []string{"Amount", "ID"}, in: "AMOUNT", want: []string{"Amount"}},
+		{name: "ambiguous", oraNames: []string{"amount", "Amount", "ID"}, in: "AMOUNT", want: []string{"amount", "Amount"}},
+		{name: "missing", oraNames: []string{"ID"}, in: "AMOUNT", want: nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := matchOracleName(c.oraNames, c.in); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("matchOracleName(%v, %q) = %v, want %v", c.oraNames, c.in, got, c.want)
+			}
+		})
+	}
+}
@@ -55,5 +55,10 @@ func SetupSignalHandler(shutdownFunc func()) {
 		sig := <-closeSignalChan
 		zap.L().Info("got signal to exit", zap.Stringer("signal", sig))
 		shutdownFunc()
+
+		// 再次收到退出信号强制退出
+		sig = <-closeSignalChan
+		zap.L().Warn("got signal again to force exit", zap.Stringer("signal", sig))
+		os.Exit(1)
 	}()
 }